@@ -17,16 +17,57 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	ResourceDeletionRemediationStrategy  = RemediationStrategyType("ResourceDeletion")
 	OutOfServiceTaintRemediationStrategy = RemediationStrategyType("OutOfServiceTaint")
+	NodeDeletionRemediationStrategy      = RemediationStrategyType("NodeDeletion")
+	RebootOnlyRemediationStrategy        = RemediationStrategyType("RebootOnly")
+	AutomaticRemediationStrategy         = RemediationStrategyType("Automatic")
 	// ProcessingConditionType is the condition type used to signal NHC the remediation status
 	ProcessingConditionType = "Processing"
 	// SucceededConditionType is the condition type used to signal NHC whether the remediation was successful or not
 	SucceededConditionType = "Succeeded"
+	// WaitingConditionType is the condition type used to signal that fencing the node is held back by the max concurrent fencing limit
+	WaitingConditionType = "Waiting"
+	// EscalatedConditionType is the condition type used to signal that the remediation was escalated to a secondary remediator
+	EscalatedConditionType = "Escalated"
+	// PostponedConditionType is the condition type used to signal that fencing the node is held back by an active blackout window
+	PostponedConditionType = "Postponed"
+	// PermanentlyFailedConditionType is the condition type used to signal that remediating the node was refused,
+	// because it was remediated too often within the configured reboot loop threshold
+	PermanentlyFailedConditionType = "PermanentlyFailed"
+	// NodeRecoveredConditionType is the condition type used to signal whether the node came back healthy after fencing was completed
+	NodeRecoveredConditionType = "NodeRecovered"
+	// RebootApprovedConditionType is the condition type used to signal whether rebooting the node was approved,
+	// when the reboot requires an approval
+	RebootApprovedConditionType = "RebootApproved"
+	// ZoneFailureSuspectedConditionType is the condition type used to signal that fencing the node is paused,
+	// because a large share of the nodes in its zone need remediation at the same time
+	ZoneFailureSuspectedConditionType = "ZoneFailureSuspected"
+	// PausedConditionType is the condition type used to signal that the remediation is paused by the SelfNodeRemediationConfig
+	PausedConditionType = "Paused"
+
+	// ExcludeFromDeletionAnnotation can be set to "true" on pods which shouldn't be deleted by the ResourceDeletion remediation strategy,
+	// e.g. pods whose controller handles fencing itself
+	ExcludeFromDeletionAnnotation = "self-node-remediation.medik8s.io/exclude-from-deletion"
+	// DeletionPriorityAnnotation can be set on pods to an integer priority for the ResourceDeletion remediation strategy.
+	// Pods with a higher priority are deleted first, e.g. for releasing stateful pods before stateless ones. The default priority is 0
+	DeletionPriorityAnnotation = "self-node-remediation.medik8s.io/deletion-priority"
+	// RemediationHistoryAnnotation is set on nodes to the comma separated creation times of their latest remediations,
+	// for detecting nodes which are remediated over and over. Removing it resets the reboot loop detection of the node
+	RemediationHistoryAnnotation = "self-node-remediation.medik8s.io/remediation-history"
+	// RebootApprovedAnnotation is set to "true" on a SelfNodeRemediation for approving the reboot of its node,
+	// when the reboot requires an approval
+	RebootApprovedAnnotation = "self-node-remediation.medik8s.io/reboot-approved"
+
+	// ApproveRebootApprovalTimeoutAction reboots the node when the approval timed out
+	ApproveRebootApprovalTimeoutAction = RebootApprovalTimeoutAction("Approve")
+	// AbortRebootApprovalTimeoutAction aborts the remediation when the approval timed out
+	AbortRebootApprovalTimeoutAction = RebootApprovalTimeoutAction("Abort")
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -37,13 +78,122 @@ type RemediationStrategyType string
 // SelfNodeRemediationSpec defines the desired state of SelfNodeRemediation
 type SelfNodeRemediationSpec struct {
 	//RemediationStrategy is the remediation method for unhealthy nodes.
-	//Currently, it could be either "ResourceDeletion" or "OutOfServiceTaint".
+	//Currently, it could be either "ResourceDeletion", "OutOfServiceTaint", "NodeDeletion", "RebootOnly" or "Automatic".
 	//The first will iterate over all pods and VolumeAttachment related to the unhealthy node and delete them.
-	//The latter will add the out-of-service taint which is a new well-known taint "node.kubernetes.io/out-of-service"
+	//The second will add the out-of-service taint which is a new well-known taint "node.kubernetes.io/out-of-service"
 	//that enables automatic deletion of pv-attached pods on failed nodes, "OutOfServiceTaint" is only supported on clusters with k8s version 1.26+ or OCP/OKD version 4.13+.
+	//The third will delete the node, so that cloud controllers and CSI attachers fully reset,
+	//and will re-create it from a backup in case its kubelet doesn't re-register in time.
+	//"RebootOnly" will reboot the node without deleting its workloads, e.g. for VMs with their own HA or pods with node-local volumes,
+	//and will succeed once the node is Ready again after it was rebooted. It fails when that doesn't happen within
+	//the RecoveryVerificationTimeout, or within 10 minutes when no timeout is set.
+	//The last will use "OutOfServiceTaint" when the cluster supports it, and "ResourceDeletion" otherwise.
 	// +kubebuilder:default:="ResourceDeletion"
-	// +kubebuilder:validation:Enum=ResourceDeletion;OutOfServiceTaint
+	// +kubebuilder:validation:Enum=ResourceDeletion;OutOfServiceTaint;NodeDeletion;RebootOnly;Automatic
 	RemediationStrategy RemediationStrategyType `json:"remediationStrategy,omitempty"`
+
+	// DryRun indicates that the remediation should only be simulated.
+	// The remediation walks through all phases and records events for the actions it would take,
+	// but the node isn't tainted nor rebooted, and its workloads aren't deleted.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// DrainTimeout enables an attempt to gracefully evict the pods of the node before fencing it,
+	// which lets the pods shut down cleanly in case the node is still partially reachable.
+	// Evictions respect PodDisruptionBudgets. When the timeout expires, the node is fenced anyway.
+	// Valid time units are "ms", "s", "m", "h".
+	// +optional
+	// +kubebuilder:validation:Pattern="^(0|([0-9]+(\\.[0-9]+)?(ms|s|m|h)))$"
+	// +kubebuilder:validation:Type:=string
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+
+	// Escalation defines a secondary remediation, which is created when the node isn't Ready in time
+	// after fencing it was completed, or in time after it was rebooted by the RebootOnly remediation strategy.
+	// +optional
+	Escalation *Escalation `json:"escalation,omitempty"`
+
+	// RecoveryVerificationTimeout enables verifying that the node came back healthy after fencing was completed,
+	// i.e. that it's Ready and was rebooted, according to its boot ID. The result is reported in the "NodeRecovered"
+	// condition, apart from the "Succeeded" condition which reports that the workloads were safely released.
+	// If the node doesn't recover within the timeout, the "NodeRecovered" condition is set to false.
+	// Valid time units are "ms", "s", "m", "h".
+	// +optional
+	// +kubebuilder:validation:Pattern="^(0|([0-9]+(\\.[0-9]+)?(ms|s|m|h)))$"
+	// +kubebuilder:validation:Type:=string
+	RecoveryVerificationTimeout *metav1.Duration `json:"recoveryVerificationTimeout,omitempty"`
+
+	// RebootApproval requires a manual approval before the node is rebooted, e.g. for critical nodes.
+	// The remediation waits after the node was fenced, until the "self-node-remediation.medik8s.io/reboot-approved"
+	// annotation is set to "true" on the SelfNodeRemediation. The state of the approval is reported in the "RebootApproved" condition.
+	// Note that a node which lost its API server access can't see the approval, and still reboots itself.
+	// +optional
+	RebootApproval *RebootApproval `json:"rebootApproval,omitempty"`
+}
+
+type RebootApprovalTimeoutAction string
+
+// RebootApproval defines how long to wait for the approval of the reboot, and what to do when it timed out
+type RebootApproval struct {
+	// Timeout is the max time to wait for the approval. When it isn't set, the remediation waits until the reboot is approved.
+	// Valid time units are "ms", "s", "m", "h".
+	// +optional
+	// +kubebuilder:validation:Pattern="^(0|([0-9]+(\\.[0-9]+)?(ms|s|m|h)))$"
+	// +kubebuilder:validation:Type:=string
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TimeoutAction is the action taken when the timeout expired, either "Approve" for rebooting the node,
+	// or "Abort" for stopping the remediation and leaving the node fenced.
+	// +kubebuilder:default:="Abort"
+	// +kubebuilder:validation:Enum=Approve;Abort
+	// +optional
+	TimeoutAction RebootApprovalTimeoutAction `json:"timeoutAction,omitempty"`
+}
+
+// Escalation defines when and how to escalate a remediation to a secondary remediator
+type Escalation struct {
+	// RemediationTemplate references the template of the secondary remediator, e.g. a machine deletion or a BMC based remediation template.
+	RemediationTemplate EscalationTemplateReference `json:"remediationTemplate"`
+
+	// Timeout is the time to wait for the node to become Ready after fencing was completed, before escalating.
+	// Valid time units are "ms", "s", "m", "h".
+	// +kubebuilder:validation:Pattern="^(0|([0-9]+(\\.[0-9]+)?(ms|s|m|h)))$"
+	// +kubebuilder:validation:Type:=string
+	Timeout metav1.Duration `json:"timeout"`
+}
+
+// EscalationTemplateReference references a remediation template in the namespace of the SelfNodeRemediation.
+// The remediation created from it has the template kind without the "Template" suffix, and is named after the SelfNodeRemediation.
+// Note that self node remediation needs permissions to get the template and to create the remediation, which are granted
+// by a ClusterRole with the remediation.medik8s.io/aggregate-to-snr=true label, usually shipped by the secondary remediator.
+type EscalationTemplateReference struct {
+	// APIVersion of the remediation template, e.g. machine-deletion-remediation.medik8s.io/v1alpha1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the remediation template, e.g. MachineDeletionRemediationTemplate
+	// +kubebuilder:validation:Pattern=".+Template$"
+	Kind string `json:"kind"`
+
+	// Name of the remediation template
+	Name string `json:"name"`
+}
+
+// NodeBackup is the part of a node, which is needed for re-creating it
+type NodeBackup struct {
+	// Name of the node
+	Name string `json:"name"`
+
+	// Labels of the node
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations of the node, without the annotations which describe the deleted node object,
+	// and which are set again by the kubelet and the node controllers
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Spec of the node
+	// +optional
+	Spec v1.NodeSpec `json:"spec,omitempty"`
 }
 
 // SelfNodeRemediationStatus defines the observed state of SelfNodeRemediation
@@ -53,6 +203,42 @@ type SelfNodeRemediationStatus struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=status
 	TimeAssumedRebooted *metav1.Time `json:"timeAssumedRebooted,omitempty"`
 
+	// RemediationStrategy is the strategy used for this remediation.
+	// It matches the spec, except for the "Automatic" strategy which is resolved when the remediation starts.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	RemediationStrategy RemediationStrategyType `json:"remediationStrategy,omitempty"`
+
+	// NodeBackup is a copy of the node spec, labels and annotations, which is used by the NodeDeletion
+	// remediation strategy for re-creating the node.
+	// +optional
+	NodeBackup *NodeBackup `json:"nodeBackup,omitempty"`
+
+	// NodeDeletionTime is the time the node was deleted by the NodeDeletion remediation strategy.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	NodeDeletionTime *metav1.Time `json:"nodeDeletionTime,omitempty"`
+
+	// BootIDBeforeFencing is the boot ID of the node when fencing started, for verifying that it was rebooted.
+	// A node which reports a different boot ID and is Ready is known to be rebooted, without waiting for TimeAssumedRebooted.
+	// +optional
+	BootIDBeforeFencing string `json:"bootIDBeforeFencing,omitempty"`
+
+	// PreRemediationNodeState is the state of the node before it was fenced, which is restored when the remediation is done.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	PreRemediationNodeState *NodeState `json:"preRemediationNodeState,omitempty"`
+
+	// Drain reports the progress of evicting the pods of the node before fencing it.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Drain *DrainStatus `json:"drain,omitempty"`
+
+	// PodDeletions reports the result of deleting each pod of the node by the ResourceDeletion remediation strategy.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	PodDeletions []PodDeletion `json:"podDeletions,omitempty"`
+
 	// Phase represents the current phase of remediation,
 	// One of: TBD
 	// +optional
@@ -66,11 +252,94 @@ type SelfNodeRemediationStatus struct {
 
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="conditions",xDescriptors="urn:alm:descriptor:com.tectonic.ui:conditions"
 	// Represents the observations of a SelfNodeRemediation's current state.
-	// Known .status.conditions.type are: "Processing"
+	// Known .status.conditions.type are: "Processing", "Succeeded", "Waiting", "Escalated", "Postponed", "PermanentlyFailed", "NodeRecovered", "RebootApproved", "ZoneFailureSuspected" and "Paused"
 	// +listType=map
 	// +listMapKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Timeline records every phase change of the remediation, in the order they happened.
+	// The end of a phase is the transition time of the entry which follows it.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Timeline []PhaseTransition `json:"timeline,omitempty"`
+}
+
+// NodeState is the part of the node state which is changed by the remediation
+type NodeState struct {
+	// Unschedulable is whether the node was cordoned
+	Unschedulable bool `json:"unschedulable"`
+
+	// Taints are the taints which are added or removed by the remediation, and which the node already had
+	// +optional
+	Taints []v1.Taint `json:"taints,omitempty"`
+}
+
+// DrainState is the state of evicting the pods of the node before fencing it
+type DrainState string
+
+const (
+	DrainInProgress = DrainState("InProgress")
+	DrainCompleted  = DrainState("Completed")
+	DrainTimedOut   = DrainState("TimedOut")
+)
+
+// DrainStatus reports the progress of evicting the pods of the node before fencing it
+type DrainStatus struct {
+	// State is one of "InProgress", "Completed" or "TimedOut"
+	State DrainState `json:"state"`
+
+	// StartTime is the time the drain started
+	StartTime metav1.Time `json:"startTime"`
+
+	// PendingPods is the number of pods which weren't evicted yet
+	PendingPods int `json:"pendingPods"`
+}
+
+// PodDeletionResult is the result of deleting a pod of the node
+type PodDeletionResult string
+
+const (
+	PodDeleted          = PodDeletionResult("Deleted")
+	PodDeletionExcluded = PodDeletionResult("Excluded")
+	PodDeletionFailed   = PodDeletionResult("Failed")
+)
+
+// PodDeletion reports the result of deleting a pod of the node
+type PodDeletion struct {
+	// Namespace of the pod
+	Namespace string `json:"namespace"`
+
+	// Name of the pod
+	Name string `json:"name"`
+
+	// Priority is the deletion priority of the pod
+	// +optional
+	Priority int `json:"priority,omitempty"`
+
+	// Result is one of "Deleted", "Excluded" or "Failed"
+	Result PodDeletionResult `json:"result"`
+
+	// Message describes why the deletion failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// PhaseTransition describes a single phase change of the remediation
+type PhaseTransition struct {
+	// Phase is the phase the remediation moved to
+	Phase string `json:"phase"`
+
+	// TransitionTime is the time the remediation moved to Phase
+	TransitionTime metav1.Time `json:"transitionTime"`
+
+	// Actor is the self node remediation manager or agent which made the transition
+	// +optional
+	Actor string `json:"actor,omitempty"`
+
+	// Reason is a short explanation for the transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -40,13 +40,13 @@ var _ webhook.Validator = &SelfNodeRemediation{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *SelfNodeRemediation) ValidateCreate() error {
 	webhookRemediationLog.Info("validate create", "name", r.Name)
-	return validateStrategy(r.Spec)
+	return validateSpec(r.Spec)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *SelfNodeRemediation) ValidateUpdate(_ runtime.Object) error {
 	webhookRemediationLog.Info("validate update", "name", r.Name)
-	return validateStrategy(r.Spec)
+	return validateSpec(r.Spec)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -31,6 +32,40 @@ const (
 	defaultIsSoftwareRebootEnabled       = true
 )
 
+// VolumeAttachmentFinalizerPolicyType defines how the finalizers of a deleted VolumeAttachment are handled
+type VolumeAttachmentFinalizerPolicyType string
+
+const (
+	// WaitVolumeAttachmentFinalizerPolicy waits for the CSI attacher to detach the volume and remove the VolumeAttachment finalizers
+	WaitVolumeAttachmentFinalizerPolicy VolumeAttachmentFinalizerPolicyType = "Wait"
+	// RemoveVolumeAttachmentFinalizerPolicy removes the VolumeAttachment finalizers, force detaching the volume without waiting for the CSI attacher
+	RemoveVolumeAttachmentFinalizerPolicy VolumeAttachmentFinalizerPolicyType = "Remove"
+)
+
+// PeerAddressPreference defines which IP addresses of a peer are tried first
+type PeerAddressPreference string
+
+const (
+	// InternalIPPeerAddressPreference tries the InternalIP addresses of a peer first
+	InternalIPPeerAddressPreference PeerAddressPreference = "InternalIP"
+	// ExternalIPPeerAddressPreference tries the ExternalIP addresses of a peer first
+	ExternalIPPeerAddressPreference PeerAddressPreference = "ExternalIP"
+	// IPv6PeerAddressPreference tries the IPv6 addresses of a peer first
+	IPv6PeerAddressPreference PeerAddressPreference = "IPv6"
+)
+
+// PeerQuorumPolicyType defines how many peers need to agree on whether a node is healthy
+type PeerQuorumPolicyType string
+
+const (
+	// FirstResponsePeerQuorumPolicy acts on the first peer which answers
+	FirstResponsePeerQuorumPolicy PeerQuorumPolicyType = "FirstResponse"
+	// MajorityPeerQuorumPolicy acts on the verdict of more than half of the peers which answered, including api errors
+	MajorityPeerQuorumPolicy PeerQuorumPolicyType = "Majority"
+	// MinAgreeingPeerQuorumPolicy acts on the verdict of a minimum number of agreeing peers
+	MinAgreeingPeerQuorumPolicy PeerQuorumPolicyType = "MinAgreeing"
+)
+
 // SelfNodeRemediationConfigSpec defines the desired state of SelfNodeRemediationConfig
 type SelfNodeRemediationConfigSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -97,6 +132,26 @@ type SelfNodeRemediationConfigSpec struct {
 	// timeout for each peer request
 	PeerRequestTimeout *metav1.Duration `json:"peerRequestTimeout,omitempty"`
 
+	// PeerAddressPreference is one of "InternalIP", "ExternalIP" and "IPv6", and defines which IP addresses of a peer
+	// the agents try first. The other InternalIP and ExternalIP addresses of the peer are tried afterwards, as long as
+	// the PeerDialTimeout allows, which is shared by all the addresses of the peer.
+	// +optional
+	// +kubebuilder:default:="InternalIP"
+	// +kubebuilder:validation:Enum=InternalIP;ExternalIP;IPv6
+	PeerAddressPreference PeerAddressPreference `json:"peerAddressPreference,omitempty"`
+
+	// PeerRackLabel is the node label which defines the rack of a node, e.g. "topology.example.com/rack".
+	// The agents ask their peers in random order, but spread over the zones, defined by the "topology.kubernetes.io/zone"
+	// node label, and over the racks within each zone, so that a single failed zone or rack doesn't decide whether a node
+	// is healthy. Racks are ignored when empty (which is the default).
+	// +optional
+	PeerRackLabel string `json:"peerRackLabel,omitempty"`
+
+	// PeerQuorum defines how many peers need to agree on whether a node is healthy, before its agent acts on it.
+	// The first peer which answers decides when empty (which is the default).
+	// +optional
+	PeerQuorum *PeerQuorum `json:"peerQuorum,omitempty"`
+
 	// +optional
 	// +kubebuilder:default:=3
 	// +kubebuilder:validation:Minimum=1
@@ -114,6 +169,17 @@ type SelfNodeRemediationConfigSpec struct {
 	// It will be ignored when empty (which is the default).
 	EndpointHealthCheckUrl string `json:"endpointHealthCheckUrl,omitempty"`
 
+	// KubeletFailureThreshold is the number of consecutive failed checks of the kubelet's /healthz endpoint, after which
+	// the self diagnostics consider the kubelet to be down. On control-plane nodes the kubelet is checked once per run of
+	// the diagnostics. On worker nodes it's checked with the api check interval, and a kubelet which is down makes the
+	// node ask its peers, which leads to a reboot when they report that they can access the api server.
+	// The kubelet's serving certificate needs to be signed by the cluster CA, kubelets with other certificates,
+	// e.g. self-signed ones, can't be checked and are considered to be running.
+	// +optional
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	KubeletFailureThreshold int `json:"kubeletFailureThreshold,omitempty"`
+
 	// HostPort is used for internal communication between SNR agents.
 	// +optional
 	// +kubebuilder:default:=30001
@@ -122,6 +188,207 @@ type SelfNodeRemediationConfigSpec struct {
 
 	// CustomDsTolerations allows to add custom tolerations snr agents that are running on the ds in order to support remediation for different types of nodes.
 	CustomDsTolerations []v1.Toleration `json:"customDsTolerations,omitempty"`
+
+	// MaxConcurrentFencing is the maximum number of nodes which may be fenced at the same time,
+	// either as an absolute number (e.g. 5) or as a percentage of the cluster nodes (e.g. 20%).
+	// Remediations above this limit are held in the "Waiting" phase until other remediations are done.
+	// The limit needs to allow fencing at least one node, so 0 and 0% are invalid.
+	// It will be ignored when empty (which is the default).
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:Pattern="^((100|[1-9][0-9]?)%|[1-9][0-9]*)$"
+	MaxConcurrentFencing *intstr.IntOrString `json:"maxConcurrentFencing,omitempty"`
+
+	// VolumeAttachmentFinalizerPolicies configures per CSI driver how the finalizers of the VolumeAttachments, which are
+	// deleted by the "ResourceDeletion" remediation strategy, are handled.
+	// VolumeAttachments of CSI drivers which aren't listed here use the "Wait" policy.
+	// +optional
+	VolumeAttachmentFinalizerPolicies []VolumeAttachmentFinalizerPolicy `json:"volumeAttachmentFinalizerPolicies,omitempty"`
+
+	// BlackoutWindows are periods, e.g. change freezes and planned maintenance, in which nodes aren't fenced.
+	// Remediations of nodes which are selected by an active window are held in the "Postponed" phase until it ends.
+	// Remediations which already started fencing their node are not affected.
+	// +optional
+	BlackoutWindows []BlackoutWindow `json:"blackoutWindows,omitempty"`
+
+	// RebootLoopThreshold detects nodes which are remediated over and over, e.g. because they keep flapping.
+	// Remediations of a node which crossed the threshold are refused with the "PermanentlyFailed" condition,
+	// and the node is left cordoned for a human to investigate.
+	// It will be ignored when empty (which is the default).
+	// +optional
+	RebootLoopThreshold *RebootLoopThreshold `json:"rebootLoopThreshold,omitempty"`
+
+	// ZoneFailureDetection pauses fencing the nodes of a zone, when a large share of the zone's nodes need remediation
+	// at the same time, which most likely is caused by a zone level network failure rather than by failures of the nodes.
+	// Zones are determined by the "topology.kubernetes.io/zone" node label. Paused remediations have the
+	// "ZoneFailureSuspected" condition, and continue once the share of unhealthy nodes drops below the threshold.
+	// It will be ignored when empty (which is the default).
+	// +optional
+	ZoneFailureDetection *ZoneFailureDetection `json:"zoneFailureDetection,omitempty"`
+
+	// Paused stops all self node remediation, e.g. during incident response, without uninstalling the operator.
+	// Remediations stop advancing and get the "Paused" condition, and agents don't reboot their node when they
+	// lose the api server. Agents which can't reach the api server use the last value they've seen.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// LocalHealthChecks are checks of the node's own health, which the agents run in addition to checking their
+	// connectivity to the api server, e.g. for detecting a dead container runtime or a read-only root filesystem.
+	// A failing check is handled like an api server error: once the failures reach the MaxApiErrorThreshold, the agent
+	// asks its peers whether it's healthy, and reboots the node when they confirm that it isn't.
+	// It will be ignored when empty (which is the default).
+	// +optional
+	LocalHealthChecks *LocalHealthChecks `json:"localHealthChecks,omitempty"`
+}
+
+// LocalHealthChecks defines which local health checks the agents run. Checks which are empty are disabled
+type LocalHealthChecks struct {
+	// Timeout for each check.
+	// Valid time units are "ms", "s", "m", "h".
+	// +optional
+	// +kubebuilder:default:="5s"
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Kubelet checks the /healthz endpoint of the node's kubelet. The kubelet's serving certificate needs to be signed
+	// by the cluster CA, e.g. by enabling the kubelet's serverTLSBootstrap.
+	// +optional
+	Kubelet *KubeletHealthCheck `json:"kubelet,omitempty"`
+
+	// ContainerRuntime checks that the container runtime answers on its CRI socket.
+	// +optional
+	ContainerRuntime *ContainerRuntimeHealthCheck `json:"containerRuntime,omitempty"`
+
+	// DiskWritability checks that a file can be written to a directory of the node.
+	// +optional
+	DiskWritability *DiskWritabilityHealthCheck `json:"diskWritability,omitempty"`
+
+	// MemoryPressure checks that enough of the node's memory is available.
+	// +optional
+	MemoryPressure *MemoryPressureHealthCheck `json:"memoryPressure,omitempty"`
+}
+
+// KubeletHealthCheck defines how the kubelet is checked
+type KubeletHealthCheck struct {
+	// Port is the https port of the kubelet.
+	// +kubebuilder:default:=10250
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port,omitempty"`
+}
+
+// ContainerRuntimeHealthCheck defines how the container runtime is checked
+type ContainerRuntimeHealthCheck struct {
+	// SocketPath is the path of the CRI socket on the node, e.g. /run/containerd/containerd.sock for containerd,
+	// or /var/run/crio/crio.sock for CRI-O.
+	// +kubebuilder:default:=/run/containerd/containerd.sock
+	// +kubebuilder:validation:Pattern="^/"
+	SocketPath string `json:"socketPath,omitempty"`
+}
+
+// DiskWritabilityHealthCheck defines how the disk writability is checked
+type DiskWritabilityHealthCheck struct {
+	// Path is a directory on the node, in which a file is written and removed again.
+	// +kubebuilder:default:=/var/tmp
+	// +kubebuilder:validation:Pattern="^/"
+	Path string `json:"path,omitempty"`
+}
+
+// MemoryPressureHealthCheck defines how the memory pressure is checked
+type MemoryPressureHealthCheck struct {
+	// MinAvailablePercentage is the percentage of the node's memory which needs to be available.
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	MinAvailablePercentage int `json:"minAvailablePercentage,omitempty"`
+}
+
+// PeerQuorum defines how many peers need to agree on whether a node is healthy
+type PeerQuorum struct {
+	// Policy is one of "FirstResponse", "Majority" and "MinAgreeing".
+	// The first acts on the first peer which answers, and prefers the healthy verdict when peers which were asked at
+	// the same time disagree. The second acts on a verdict once more than half of the peers which answered agree on it,
+	// where peers which answered that they can't reach the api server count as answered without a verdict.
+	// The third acts on a verdict once at least MinAgreeingPeers peers agree on it.
+	// Peers are asked in batches until the policy is met. When no verdict meets it after all peers were asked,
+	// the node is considered healthy for now, and its peers are asked again on the next error.
+	// +kubebuilder:default:="FirstResponse"
+	// +kubebuilder:validation:Enum=FirstResponse;Majority;MinAgreeing
+	Policy PeerQuorumPolicyType `json:"policy,omitempty"`
+
+	// MinAgreeingPeers is the number of peers which need to agree on a verdict for the "MinAgreeing" policy.
+	// Clusters with less peers need all of them to agree.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinAgreeingPeers int `json:"minAgreeingPeers,omitempty"`
+}
+
+// ZoneFailureDetection defines which share of a zone's nodes needing remediation at the same time is considered a zone failure
+type ZoneFailureDetection struct {
+	// UnhealthyNodesPercentage is the percentage of the nodes in a zone, which have remediations created within the time window,
+	// from which on a zone failure is suspected. A zone failure is only suspected for more than one unhealthy node.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	UnhealthyNodesPercentage int `json:"unhealthyNodesPercentage"`
+
+	// TimeWindow is the max time between the creation of remediations, for considering them as created at the same time.
+	// Valid time units are "ms", "s", "m", "h".
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	TimeWindow metav1.Duration `json:"timeWindow"`
+}
+
+// RebootLoopThreshold defines how many remediations of a node within a time window are allowed
+type RebootLoopThreshold struct {
+	// MaxRemediations is the number of remediations of a node within the time window, after which further
+	// remediations of the node are refused.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	MaxRemediations int `json:"maxRemediations"`
+
+	// TimeWindow is the period in which remediations of a node are counted.
+	// Valid time units are "ms", "s", "m", "h".
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	TimeWindow metav1.Duration `json:"timeWindow"`
+}
+
+// BlackoutWindow is a recurring period in which nodes aren't fenced
+type BlackoutWindow struct {
+	// Name identifies the window in the conditions and events of postponed remediations.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Schedule is a cron expression in UTC, with the five standard fields (minute, hour, day of month, month and
+	// day of week), of the times the window starts, e.g. "0 22 * * 5" for every Friday at 22:00.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays active after it starts.
+	// Valid time units are "ms", "s", "m", "h".
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	Duration metav1.Duration `json:"duration"`
+
+	// NodeSelector selects the nodes the window applies to. The window applies to all nodes when it's empty.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+// VolumeAttachmentFinalizerPolicy defines how the finalizers of the VolumeAttachments of a CSI driver are handled
+type VolumeAttachmentFinalizerPolicy struct {
+	// Driver is the name of the CSI driver, as it appears in the VolumeAttachment attacher field.
+	// +kubebuilder:validation:MinLength=1
+	Driver string `json:"driver"`
+
+	// Policy is one of "Wait" and "Remove".
+	// The first waits for the CSI driver to detach the volume and remove the finalizers of the VolumeAttachment.
+	// The second removes the finalizers, force detaching the volume from the unhealthy node, for CSI drivers which
+	// can't detach volumes from a node which is down.
+	// +kubebuilder:default:="Wait"
+	// +kubebuilder:validation:Enum=Wait;Remove
+	Policy VolumeAttachmentFinalizerPolicyType `json:"policy,omitempty"`
 }
 
 // SelfNodeRemediationConfigStatus defines the observed state of SelfNodeRemediationConfig
@@ -21,11 +21,15 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/medik8s/self-node-remediation/pkg/utils"
 )
 
 // fields names
@@ -74,6 +78,11 @@ func (r *SelfNodeRemediationConfig) ValidateCreate() error {
 	return errors.NewAggregate([]error{
 		r.validateTimes(),
 		r.validateCustomTolerations(),
+		r.validateMaxConcurrentFencing(),
+		r.validateBlackoutWindows(),
+		r.validateRebootLoopThreshold(),
+		r.validateZoneFailureDetection(),
+		r.validatePeerQuorum(),
 	})
 
 }
@@ -85,6 +94,11 @@ func (r *SelfNodeRemediationConfig) ValidateUpdate(_ runtime.Object) error {
 	return errors.NewAggregate([]error{
 		r.validateTimes(),
 		r.validateCustomTolerations(),
+		r.validateMaxConcurrentFencing(),
+		r.validateBlackoutWindows(),
+		r.validateRebootLoopThreshold(),
+		r.validateZoneFailureDetection(),
+		r.validatePeerQuorum(),
 	})
 }
 
@@ -142,6 +156,66 @@ func (r *SelfNodeRemediationConfig) validateCustomTolerations() error {
 	return nil
 }
 
+// validateMaxConcurrentFencing validates that the max concurrent fencing limit is either a positive number or a valid
+// non-zero percentage, since a zero limit would silently block fencing any node
+func (r *SelfNodeRemediationConfig) validateMaxConcurrentFencing() error {
+	maxConcurrentFencing := r.Spec.MaxConcurrentFencing
+	if maxConcurrentFencing == nil {
+		return nil
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(maxConcurrentFencing, 100, false)
+	if err != nil {
+		return fmt.Errorf("invalid value for maxConcurrentFencing: %s", maxConcurrentFencing.String())
+	}
+	if value < 1 || (maxConcurrentFencing.Type == intstr.String && value > 100) {
+		return fmt.Errorf("invalid value for maxConcurrentFencing: %s", maxConcurrentFencing.String())
+	}
+	return nil
+}
+
+// validateBlackoutWindows validates that the blackout windows have a valid cron schedule, a positive duration and a valid node selector
+func (r *SelfNodeRemediationConfig) validateBlackoutWindows() error {
+	for _, window := range r.Spec.BlackoutWindows {
+		if _, err := utils.ParseCronSchedule(window.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule for blackout window %s: %v", window.Name, err)
+		}
+		if window.Duration.Duration <= 0 {
+			return fmt.Errorf("invalid duration for blackout window %s: %s", window.Name, window.Duration.Duration)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(window.NodeSelector); err != nil {
+			return fmt.Errorf("invalid node selector for blackout window %s: %v", window.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateRebootLoopThreshold validates that the reboot loop threshold time window is positive
+func (r *SelfNodeRemediationConfig) validateRebootLoopThreshold() error {
+	threshold := r.Spec.RebootLoopThreshold
+	if threshold != nil && threshold.TimeWindow.Duration <= 0 {
+		return fmt.Errorf("invalid time window for rebootLoopThreshold: %s", threshold.TimeWindow.Duration)
+	}
+	return nil
+}
+
+// validateZoneFailureDetection validates that the zone failure detection time window is positive
+func (r *SelfNodeRemediationConfig) validateZoneFailureDetection() error {
+	detection := r.Spec.ZoneFailureDetection
+	if detection != nil && detection.TimeWindow.Duration <= 0 {
+		return fmt.Errorf("invalid time window for zoneFailureDetection: %s", detection.TimeWindow.Duration)
+	}
+	return nil
+}
+
+// validatePeerQuorum validates that the min agreeing peers are set for the MinAgreeing policy
+func (r *SelfNodeRemediationConfig) validatePeerQuorum() error {
+	quorum := r.Spec.PeerQuorum
+	if quorum != nil && quorum.Policy == MinAgreeingPeerQuorumPolicy && quorum.MinAgreeingPeers < 1 {
+		return fmt.Errorf("minAgreeingPeers of peerQuorum must be at least 1 for the %s policy", MinAgreeingPeerQuorumPolicy)
+	}
+	return nil
+}
+
 func validateToleration(toleration v1.Toleration) error {
 	if len(toleration.Operator) > 0 {
 		switch toleration.Operator {
@@ -9,6 +9,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 )
 
@@ -130,6 +131,107 @@ func testSingleInvalidField(validationType string) {
 			Expect(err.Error()).To(ContainSubstring("invalid value for toleration, value must be empty for Operator value is Exists"))
 		})
 	})
+
+	Context(fmt.Sprintf("%s validation of max concurrent fencing", validationType), func() {
+		for _, invalidValue := range []intstr.IntOrString{intstr.FromInt(-1), intstr.FromInt(0), intstr.FromString("0%"), intstr.FromString("120%"), intstr.FromString("abc")} {
+			invalidValue := invalidValue
+			It(fmt.Sprintf("should be rejected - %s", invalidValue.String()), func() {
+				snrc := createDefaultSelfNodeRemediationConfigCR()
+				snrc.Spec.MaxConcurrentFencing = &invalidValue
+
+				var err error
+				if validationType == "update" {
+					snrcOld := createDefaultSelfNodeRemediationConfigCR()
+					err = snrc.ValidateUpdate(snrcOld)
+				} else {
+					err = snrc.ValidateCreate()
+				}
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid value for maxConcurrentFencing: " + invalidValue.String()))
+			})
+		}
+	})
+
+	Context(fmt.Sprintf("%s validation of blackout windows", validationType), func() {
+		invalidWindows := map[string]BlackoutWindow{
+			"invalid schedule for blackout window freeze":      {Name: "freeze", Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			"invalid duration for blackout window freeze":      {Name: "freeze", Schedule: "0 22 * * 5", Duration: metav1.Duration{Duration: 0}},
+			"invalid node selector for blackout window freeze": {Name: "freeze", Schedule: "0 22 * * 5", Duration: metav1.Duration{Duration: time.Hour}, NodeSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "zone", Operator: "Invalid"}}}},
+		}
+		for expectedErr, invalidWindow := range invalidWindows {
+			expectedErr, invalidWindow := expectedErr, invalidWindow
+			It(fmt.Sprintf("should be rejected - %s", expectedErr), func() {
+				snrc := createDefaultSelfNodeRemediationConfigCR()
+				snrc.Spec.BlackoutWindows = []BlackoutWindow{invalidWindow}
+
+				var err error
+				if validationType == "update" {
+					snrcOld := createDefaultSelfNodeRemediationConfigCR()
+					err = snrc.ValidateUpdate(snrcOld)
+				} else {
+					err = snrc.ValidateCreate()
+				}
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedErr))
+			})
+		}
+	})
+
+	Context(fmt.Sprintf("%s validation of reboot loop threshold", validationType), func() {
+		It("should be rejected - zero time window", func() {
+			snrc := createDefaultSelfNodeRemediationConfigCR()
+			snrc.Spec.RebootLoopThreshold = &RebootLoopThreshold{MaxRemediations: 3, TimeWindow: metav1.Duration{Duration: 0}}
+
+			var err error
+			if validationType == "update" {
+				snrcOld := createDefaultSelfNodeRemediationConfigCR()
+				err = snrc.ValidateUpdate(snrcOld)
+			} else {
+				err = snrc.ValidateCreate()
+			}
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid time window for rebootLoopThreshold: 0s"))
+		})
+	})
+
+	Context(fmt.Sprintf("%s validation of zone failure detection", validationType), func() {
+		It("should be rejected - zero time window", func() {
+			snrc := createDefaultSelfNodeRemediationConfigCR()
+			snrc.Spec.ZoneFailureDetection = &ZoneFailureDetection{UnhealthyNodesPercentage: 60, TimeWindow: metav1.Duration{Duration: 0}}
+
+			var err error
+			if validationType == "update" {
+				snrcOld := createDefaultSelfNodeRemediationConfigCR()
+				err = snrc.ValidateUpdate(snrcOld)
+			} else {
+				err = snrc.ValidateCreate()
+			}
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid time window for zoneFailureDetection: 0s"))
+		})
+	})
+
+	Context(fmt.Sprintf("%s validation of peer quorum", validationType), func() {
+		It("should be rejected - min agreeing policy without min agreeing peers", func() {
+			snrc := createDefaultSelfNodeRemediationConfigCR()
+			snrc.Spec.PeerQuorum = &PeerQuorum{Policy: MinAgreeingPeerQuorumPolicy}
+
+			var err error
+			if validationType == "update" {
+				snrcOld := createDefaultSelfNodeRemediationConfigCR()
+				err = snrc.ValidateUpdate(snrcOld)
+			} else {
+				err = snrc.ValidateCreate()
+			}
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("minAgreeingPeers of peerQuorum must be at least 1 for the MinAgreeing policy"))
+		})
+	})
 }
 
 func testMultipleInvalidFields(validationType string) {
@@ -174,6 +276,11 @@ func testValidCR(validationType string) {
 	snrc.Spec.ApiCheckInterval = &metav1.Duration{Duration: 10*time.Second + 500*time.Millisecond}
 	snrc.Spec.PeerUpdateInterval = &metav1.Duration{Duration: 10 * time.Second}
 	snrc.Spec.CustomDsTolerations = []v1.Toleration{{Key: "validValue", Effect: v1.TaintEffectNoExecute}, {}, {Operator: v1.TolerationOpEqual, TolerationSeconds: pointer.Int64(-5)}, {Value: "SomeValidValue"}}
+	snrc.Spec.MaxConcurrentFencing = &intstr.IntOrString{Type: intstr.String, StrVal: "20%"}
+	snrc.Spec.RebootLoopThreshold = &RebootLoopThreshold{MaxRemediations: 3, TimeWindow: metav1.Duration{Duration: time.Hour}}
+	snrc.Spec.ZoneFailureDetection = &ZoneFailureDetection{UnhealthyNodesPercentage: 60, TimeWindow: metav1.Duration{Duration: time.Minute}}
+	snrc.Spec.PeerQuorum = &PeerQuorum{Policy: MinAgreeingPeerQuorumPolicy, MinAgreeingPeers: 2}
+	snrc.Spec.BlackoutWindows = []BlackoutWindow{{Name: "freeze", Schedule: "0 22 * * 5", Duration: metav1.Duration{Duration: 56 * time.Hour}, NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}}}
 
 	Context("for valid CR", func() {
 		It("should not be rejected", func() {
@@ -22,6 +22,7 @@ import (
 
 const (
 	resourceDeletionTemplateName = "self-node-remediation-resource-deletion-template"
+	automaticTemplateName        = "self-node-remediation-automatic-strategy-template"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -84,5 +85,17 @@ func NewRemediationTemplates() []*SelfNodeRemediationTemplate {
 				},
 			},
 		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: automaticTemplateName,
+			},
+			Spec: SelfNodeRemediationTemplateSpec{
+				Template: SelfNodeRemediationTemplateResource{
+					Spec: SelfNodeRemediationSpec{
+						RemediationStrategy: AutomaticRemediationStrategy,
+					},
+				},
+			},
+		},
 	}
 }
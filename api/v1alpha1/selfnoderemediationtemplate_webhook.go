@@ -18,8 +18,10 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -45,13 +47,13 @@ var _ webhook.Validator = &SelfNodeRemediationTemplate{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *SelfNodeRemediationTemplate) ValidateCreate() error {
 	webhookTemplateLog.Info("validate create", "name", r.Name)
-	return validateStrategy(r.Spec.Template.Spec)
+	return validateSpec(r.Spec.Template.Spec)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *SelfNodeRemediationTemplate) ValidateUpdate(_ runtime.Object) error {
 	webhookTemplateLog.Info("validate update", "name", r.Name)
-	return validateStrategy(r.Spec.Template.Spec)
+	return validateSpec(r.Spec.Template.Spec)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -61,9 +63,39 @@ func (r *SelfNodeRemediationTemplate) ValidateDelete() error {
 	return nil
 }
 
+func validateSpec(snrSpec SelfNodeRemediationSpec) error {
+	if err := validateStrategy(snrSpec); err != nil {
+		return err
+	}
+	return validateEscalation(snrSpec)
+}
+
 func validateStrategy(snrSpec SelfNodeRemediationSpec) error {
 	if snrSpec.RemediationStrategy == OutOfServiceTaintRemediationStrategy && !utils.IsOutOfServiceTaintSupported {
 		return fmt.Errorf("%s remediation strategy is not supported at kubernetes version lower than 1.26, please use a different remediation strategy", OutOfServiceTaintRemediationStrategy)
 	}
 	return nil
 }
+
+// validateEscalation validates that the escalation references the template of a secondary remediator
+func validateEscalation(snrSpec SelfNodeRemediationSpec) error {
+	if snrSpec.Escalation == nil {
+		return nil
+	}
+
+	templateRef := snrSpec.Escalation.RemediationTemplate
+	gv, err := schema.ParseGroupVersion(templateRef.APIVersion)
+	if err != nil {
+		return fmt.Errorf("invalid apiVersion %s of the escalation remediation template: %v", templateRef.APIVersion, err)
+	}
+	if gv.Group == "" || gv.Version == "" {
+		return fmt.Errorf("apiVersion %s of the escalation remediation template must have a group and a version", templateRef.APIVersion)
+	}
+	if gv.Group == GroupVersion.Group {
+		return fmt.Errorf("escalation remediation template must belong to a secondary remediator, but its group is %s", gv.Group)
+	}
+	if kind := strings.TrimSuffix(templateRef.Kind, "Template"); kind == "" || kind == templateRef.Kind {
+		return fmt.Errorf("kind %s of the escalation remediation template must be the remediation kind with a Template suffix", templateRef.Kind)
+	}
+	return nil
+}
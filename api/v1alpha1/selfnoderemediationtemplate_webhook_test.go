@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -50,6 +52,19 @@ var _ = Describe("SelfNodeRemediationTemplate Validation", func() {
 			})
 		})
 
+		Context("with automatic strategy", func() {
+			BeforeEach(func() {
+				orgValue := utils.IsOutOfServiceTaintSupported
+				DeferCleanup(func() { utils.IsOutOfServiceTaintSupported = orgValue })
+				utils.IsOutOfServiceTaintSupported = false
+				snrtValid.Spec.Template.Spec.RemediationStrategy = AutomaticRemediationStrategy
+			})
+			It("should be allowed even when out of service taint is not supported", func() {
+				Expect(snrtValid.ValidateCreate()).To(Succeed())
+				Expect(snrtValid.ValidateUpdate(outOfServiceStrategy)).To(Succeed())
+			})
+		})
+
 		Context("with out Of Service Taint strategy", func() {
 			BeforeEach(func() {
 				orgValue := utils.IsOutOfServiceTaintSupported
@@ -77,6 +92,44 @@ var _ = Describe("SelfNodeRemediationTemplate Validation", func() {
 
 		})
 
+		Context("with escalation", func() {
+			BeforeEach(func() {
+				snrtValid.Spec.Template.Spec.Escalation = &Escalation{
+					RemediationTemplate: EscalationTemplateReference{
+						APIVersion: "machine-deletion-remediation.medik8s.io/v1alpha1",
+						Kind:       "MachineDeletionRemediationTemplate",
+						Name:       "test",
+					},
+					Timeout: metav1.Duration{Duration: time.Minute},
+				}
+			})
+
+			It("should be allowed with a template of a secondary remediator", func() {
+				Expect(snrtValid.ValidateCreate()).To(Succeed())
+			})
+
+			It("should be denied with an apiVersion without group", func() {
+				snrtValid.Spec.Template.Spec.Escalation.RemediationTemplate.APIVersion = "v1"
+				Expect(snrtValid.ValidateCreate()).To(MatchError(ContainSubstring("must have a group and a version")))
+			})
+
+			It("should be denied with an invalid apiVersion", func() {
+				snrtValid.Spec.Template.Spec.Escalation.RemediationTemplate.APIVersion = "a/b/c"
+				Expect(snrtValid.ValidateCreate()).To(MatchError(ContainSubstring("invalid apiVersion a/b/c")))
+			})
+
+			It("should be denied with a self node remediation template", func() {
+				snrtValid.Spec.Template.Spec.Escalation.RemediationTemplate.APIVersion = GroupVersion.String()
+				snrtValid.Spec.Template.Spec.Escalation.RemediationTemplate.Kind = "SelfNodeRemediationTemplate"
+				Expect(snrtValid.ValidateUpdate(outOfServiceStrategy)).To(MatchError(ContainSubstring("must belong to a secondary remediator")))
+			})
+
+			It("should be denied with a kind which isn't a template", func() {
+				snrtValid.Spec.Template.Spec.Escalation.RemediationTemplate.Kind = "MachineDeletionRemediation"
+				Expect(snrtValid.ValidateCreate()).To(MatchError(ContainSubstring("must be the remediation kind with a Template suffix")))
+			})
+		})
+
 	})
 
 })
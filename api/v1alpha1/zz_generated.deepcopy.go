@@ -25,14 +25,318 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackoutWindow) DeepCopyInto(out *BlackoutWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackoutWindow.
+func (in *BlackoutWindow) DeepCopy() *BlackoutWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackoutWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRuntimeHealthCheck) DeepCopyInto(out *ContainerRuntimeHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRuntimeHealthCheck.
+func (in *ContainerRuntimeHealthCheck) DeepCopy() *ContainerRuntimeHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRuntimeHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskWritabilityHealthCheck) DeepCopyInto(out *DiskWritabilityHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskWritabilityHealthCheck.
+func (in *DiskWritabilityHealthCheck) DeepCopy() *DiskWritabilityHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskWritabilityHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainStatus) DeepCopyInto(out *DrainStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainStatus.
+func (in *DrainStatus) DeepCopy() *DrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Escalation) DeepCopyInto(out *Escalation) {
+	*out = *in
+	out.RemediationTemplate = in.RemediationTemplate
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Escalation.
+func (in *Escalation) DeepCopy() *Escalation {
+	if in == nil {
+		return nil
+	}
+	out := new(Escalation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EscalationTemplateReference) DeepCopyInto(out *EscalationTemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EscalationTemplateReference.
+func (in *EscalationTemplateReference) DeepCopy() *EscalationTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(EscalationTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletHealthCheck) DeepCopyInto(out *KubeletHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletHealthCheck.
+func (in *KubeletHealthCheck) DeepCopy() *KubeletHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalHealthChecks) DeepCopyInto(out *LocalHealthChecks) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(KubeletHealthCheck)
+		**out = **in
+	}
+	if in.ContainerRuntime != nil {
+		in, out := &in.ContainerRuntime, &out.ContainerRuntime
+		*out = new(ContainerRuntimeHealthCheck)
+		**out = **in
+	}
+	if in.DiskWritability != nil {
+		in, out := &in.DiskWritability, &out.DiskWritability
+		*out = new(DiskWritabilityHealthCheck)
+		**out = **in
+	}
+	if in.MemoryPressure != nil {
+		in, out := &in.MemoryPressure, &out.MemoryPressure
+		*out = new(MemoryPressureHealthCheck)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalHealthChecks.
+func (in *LocalHealthChecks) DeepCopy() *LocalHealthChecks {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalHealthChecks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryPressureHealthCheck) DeepCopyInto(out *MemoryPressureHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryPressureHealthCheck.
+func (in *MemoryPressureHealthCheck) DeepCopy() *MemoryPressureHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryPressureHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeBackup) DeepCopyInto(out *NodeBackup) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBackup.
+func (in *NodeBackup) DeepCopy() *NodeBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeState) DeepCopyInto(out *NodeState) {
+	*out = *in
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeState.
+func (in *NodeState) DeepCopy() *NodeState {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PeerQuorum) DeepCopyInto(out *PeerQuorum) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PeerQuorum.
+func (in *PeerQuorum) DeepCopy() *PeerQuorum {
+	if in == nil {
+		return nil
+	}
+	out := new(PeerQuorum)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTransition) DeepCopyInto(out *PhaseTransition) {
+	*out = *in
+	in.TransitionTime.DeepCopyInto(&out.TransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTransition.
+func (in *PhaseTransition) DeepCopy() *PhaseTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDeletion) DeepCopyInto(out *PodDeletion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDeletion.
+func (in *PodDeletion) DeepCopy() *PodDeletion {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RebootApproval) DeepCopyInto(out *RebootApproval) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebootApproval.
+func (in *RebootApproval) DeepCopy() *RebootApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(RebootApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RebootLoopThreshold) DeepCopyInto(out *RebootLoopThreshold) {
+	*out = *in
+	out.TimeWindow = in.TimeWindow
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RebootLoopThreshold.
+func (in *RebootLoopThreshold) DeepCopy() *RebootLoopThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(RebootLoopThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfNodeRemediation) DeepCopyInto(out *SelfNodeRemediation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -146,6 +450,11 @@ func (in *SelfNodeRemediationConfigSpec) DeepCopyInto(out *SelfNodeRemediationCo
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.PeerQuorum != nil {
+		in, out := &in.PeerQuorum, &out.PeerQuorum
+		*out = new(PeerQuorum)
+		**out = **in
+	}
 	if in.CustomDsTolerations != nil {
 		in, out := &in.CustomDsTolerations, &out.CustomDsTolerations
 		*out = make([]corev1.Toleration, len(*in))
@@ -153,6 +462,38 @@ func (in *SelfNodeRemediationConfigSpec) DeepCopyInto(out *SelfNodeRemediationCo
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MaxConcurrentFencing != nil {
+		in, out := &in.MaxConcurrentFencing, &out.MaxConcurrentFencing
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.VolumeAttachmentFinalizerPolicies != nil {
+		in, out := &in.VolumeAttachmentFinalizerPolicies, &out.VolumeAttachmentFinalizerPolicies
+		*out = make([]VolumeAttachmentFinalizerPolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlackoutWindows != nil {
+		in, out := &in.BlackoutWindows, &out.BlackoutWindows
+		*out = make([]BlackoutWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RebootLoopThreshold != nil {
+		in, out := &in.RebootLoopThreshold, &out.RebootLoopThreshold
+		*out = new(RebootLoopThreshold)
+		**out = **in
+	}
+	if in.ZoneFailureDetection != nil {
+		in, out := &in.ZoneFailureDetection, &out.ZoneFailureDetection
+		*out = new(ZoneFailureDetection)
+		**out = **in
+	}
+	if in.LocalHealthChecks != nil {
+		in, out := &in.LocalHealthChecks, &out.LocalHealthChecks
+		*out = new(LocalHealthChecks)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfNodeRemediationConfigSpec.
@@ -215,6 +556,26 @@ func (in *SelfNodeRemediationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfNodeRemediationSpec) DeepCopyInto(out *SelfNodeRemediationSpec) {
 	*out = *in
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Escalation != nil {
+		in, out := &in.Escalation, &out.Escalation
+		*out = new(Escalation)
+		**out = **in
+	}
+	if in.RecoveryVerificationTimeout != nil {
+		in, out := &in.RecoveryVerificationTimeout, &out.RecoveryVerificationTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RebootApproval != nil {
+		in, out := &in.RebootApproval, &out.RebootApproval
+		*out = new(RebootApproval)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfNodeRemediationSpec.
@@ -234,6 +595,30 @@ func (in *SelfNodeRemediationStatus) DeepCopyInto(out *SelfNodeRemediationStatus
 		in, out := &in.TimeAssumedRebooted, &out.TimeAssumedRebooted
 		*out = (*in).DeepCopy()
 	}
+	if in.NodeBackup != nil {
+		in, out := &in.NodeBackup, &out.NodeBackup
+		*out = new(NodeBackup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeDeletionTime != nil {
+		in, out := &in.NodeDeletionTime, &out.NodeDeletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreRemediationNodeState != nil {
+		in, out := &in.PreRemediationNodeState, &out.PreRemediationNodeState
+		*out = new(NodeState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(DrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDeletions != nil {
+		in, out := &in.PodDeletions, &out.PodDeletions
+		*out = make([]PodDeletion, len(*in))
+		copy(*out, *in)
+	}
 	if in.Phase != nil {
 		in, out := &in.Phase, &out.Phase
 		*out = new(string)
@@ -246,6 +631,13 @@ func (in *SelfNodeRemediationStatus) DeepCopyInto(out *SelfNodeRemediationStatus
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Timeline != nil {
+		in, out := &in.Timeline, &out.Timeline
+		*out = make([]PhaseTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfNodeRemediationStatus.
@@ -263,7 +655,7 @@ func (in *SelfNodeRemediationTemplate) DeepCopyInto(out *SelfNodeRemediationTemp
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	out.Status = in.Status
 }
 
@@ -320,7 +712,7 @@ func (in *SelfNodeRemediationTemplateList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfNodeRemediationTemplateResource) DeepCopyInto(out *SelfNodeRemediationTemplateResource) {
 	*out = *in
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfNodeRemediationTemplateResource.
@@ -336,7 +728,7 @@ func (in *SelfNodeRemediationTemplateResource) DeepCopy() *SelfNodeRemediationTe
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SelfNodeRemediationTemplateSpec) DeepCopyInto(out *SelfNodeRemediationTemplateSpec) {
 	*out = *in
-	out.Template = in.Template
+	in.Template.DeepCopyInto(&out.Template)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SelfNodeRemediationTemplateSpec.
@@ -363,3 +755,34 @@ func (in *SelfNodeRemediationTemplateStatus) DeepCopy() *SelfNodeRemediationTemp
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeAttachmentFinalizerPolicy) DeepCopyInto(out *VolumeAttachmentFinalizerPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAttachmentFinalizerPolicy.
+func (in *VolumeAttachmentFinalizerPolicy) DeepCopy() *VolumeAttachmentFinalizerPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeAttachmentFinalizerPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneFailureDetection) DeepCopyInto(out *ZoneFailureDetection) {
+	*out = *in
+	out.TimeWindow = in.TimeWindow
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneFailureDetection.
+func (in *ZoneFailureDetection) DeepCopy() *ZoneFailureDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneFailureDetection)
+	in.DeepCopyInto(out)
+	return out
+}
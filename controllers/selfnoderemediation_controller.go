@@ -19,28 +19,40 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/medik8s/common/pkg/resources"
 	"github.com/pkg/errors"
 
 	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/openshift/api/machine/v1beta1"
 
 	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+	"github.com/medik8s/self-node-remediation/pkg/metrics"
 	"github.com/medik8s/self-node-remediation/pkg/reboot"
 	"github.com/medik8s/self-node-remediation/pkg/utils"
 )
@@ -49,8 +61,31 @@ const (
 	SNRFinalizer         = "self-node-remediation.medik8s.io/snr-finalizer"
 	nhcTimeOutAnnotation = "remediation.medik8s.io/nhc-timed-out"
 
-	eventReasonRemediationCreated = "RemediationCreated"
-	eventReasonRemediationStopped = "RemediationStopped"
+	// OpenShiftMachineAPIGroup is the API group of OpenShift Machines
+	OpenShiftMachineAPIGroup = "machine.openshift.io"
+	// ClusterAPIMachineAPIGroup is the API group of Cluster API Machines
+	ClusterAPIMachineAPIGroup = "cluster.x-k8s.io"
+	// podNodeNameField is the field pods are indexed by, for listing the pods of a node
+	podNodeNameField = "spec.nodeName"
+	// escalationAggregationLabel is the label of ClusterRoles, which grant the permissions needed for escalating to a
+	// secondary remediator. They are aggregated into the escalation ClusterRole of the manager
+	escalationAggregationLabel = "remediation.medik8s.io/aggregate-to-snr"
+
+	eventReasonRemediationCreated          = "RemediationCreated"
+	eventReasonRemediationStopped          = "RemediationStopped"
+	eventReasonRemediationWaiting          = "RemediationWaiting"
+	eventReasonRemediationEscalated        = "RemediationEscalated"
+	eventReasonRemediationEscalationFailed = "RemediationEscalationFailed"
+	eventReasonRemediationPostponed        = "RemediationPostponed"
+	eventReasonRemediationResumed          = "RemediationResumed"
+	eventReasonRemediationRefused          = "RemediationRefused"
+	eventReasonNodeRecovered               = "NodeRecovered"
+	eventReasonNodeNotRecovered            = "NodeNotRecovered"
+	eventReasonAwaitingApproval            = "RemediationAwaitingApproval"
+	eventReasonRebootApproved              = "RebootApproved"
+	eventReasonRemediationAborted          = "RemediationAborted"
+	eventReasonZoneFailureSuspected        = "ZoneFailureSuspected"
+	eventReasonRemediationPaused           = "RemediationPaused"
 
 	//remediation
 	eventReasonAddFinalizer              = "AddFinalizer"
@@ -62,8 +97,46 @@ const (
 	eventReasonRemoveFinalizer           = "RemoveFinalizer"
 	eventReasonRemoveNoExecute           = "RemoveNoExecuteTaint"
 	eventReasonNodeReboot                = "NodeReboot"
-
-	eventTypeNormal = "Normal"
+	eventReasonDryRun                    = "DryRun"
+	eventReasonDrainCompleted            = "DrainCompleted"
+	eventReasonDrainTimedOut             = "DrainTimedOut"
+	eventReasonDeleteNode                = "DeleteNode"
+	eventReasonRestoreNode               = "RestoreNode"
+
+	eventTypeNormal  = "Normal"
+	eventTypeWarning = "Warning"
+
+	// the max number of remediation times kept in the remediation history annotation of a node
+	maxRemediationHistoryLength = 50
+	// the reason of the PermanentlyFailed condition
+	rebootLoopDetectedReason = "RebootLoopDetected"
+	// the reasons of the RebootApproved condition
+	approvalPendingReason  = "ApprovalPending"
+	rebootApprovedReason   = "Approved"
+	approvalTimedOutReason = "ApprovalTimedOut"
+	// the reasons of the ZoneFailureSuspected condition
+	zoneFailureDetectedReason    = "ZoneFailureDetected"
+	zoneFailureNotDetectedReason = "ZoneFailureNotDetected"
+	// a single unhealthy node is never considered a zone failure, even if it's the only node of its zone
+	minZoneFailureUnhealthyNodes = 2
+	// the reasons of the Paused condition
+	remediationPausedReason   = "RemediationPaused"
+	remediationUnpausedReason = "RemediationUnpaused"
+
+	// the interval in which a waiting remediation checks whether it can start fencing the node
+	waitingForFencingRequeueInterval = 15 * time.Second
+	// the max interval in which a postponed remediation checks whether the blackout window is still active,
+	// so that changes to the blackout windows are noticed before the window ends
+	postponedRequeueInterval = time.Minute
+	// the interval in which the progress of draining the node is checked
+	drainRequeueInterval = 5 * time.Second
+	// the interval in which a node is checked for having recovered after fencing was completed
+	recoveryVerificationRequeueInterval = 5 * time.Second
+	// the time the RebootOnly remediation strategy waits for the node to be Ready after it was rebooted,
+	// when the snr doesn't set a recovery verification timeout
+	defaultRebootOnlyRecoveryTimeout = 10 * time.Minute
+	// the max interval in which a node, which is assumed to be rebooting, is checked for reporting a new boot ID
+	bootIDCheckRequeueInterval = 5 * time.Second
 )
 
 var (
@@ -86,6 +159,20 @@ var (
 
 	lastSeenSnrNamespace  string
 	wasLastSeenSnrMachine bool
+
+	// nodeSpecificAnnotationPrefixes are the prefixes of annotations, which describe the node object and are set again
+	// by the kubelet and the node controllers, so they must not be restored when re-creating a node,
+	// e.g. restoring the OVN annotations of the deleted node breaks the networking of the re-created one
+	nodeSpecificAnnotationPrefixes = []string{
+		"k8s.ovn.org/",
+		"node.alpha.kubernetes.io/ttl",
+		"csi.volume.kubernetes.io/nodeid",
+		"volumes.kubernetes.io/controller-managed-attach-detach",
+	}
+
+	// podIndexers holds the field indexers which already index pods by node name, since several reconcilers
+	// might share a manager, and registering an index twice fails
+	podIndexers sync.Map
 )
 
 type processingChangeReason string
@@ -95,18 +182,40 @@ const (
 	remediationTimeoutByNHC         processingChangeReason = "RemediationTimeoutByNHC"
 	remediationFinishedSuccessfully processingChangeReason = "RemediationFinishedSuccessfully"
 	remediationFinishedNodeNotFound processingChangeReason = "RemediationFinishedNodeNotFound"
+	remediationPermanentlyFailed    processingChangeReason = "RemediationPermanentlyFailed"
+	remediationAborted              processingChangeReason = "RemediationAborted"
+	remediationRecoveryTimedOut     processingChangeReason = "RemediationRecoveryTimedOut"
 )
 
 type remediationPhase string
 
 const (
+	waitingPhase            remediationPhase = "Waiting"
+	postponedPhase          remediationPhase = "Postponed"
 	fencingStartedPhase     remediationPhase = "Fencing-Started"
 	preRebootCompletedPhase remediationPhase = "Pre-Reboot-Completed"
 	rebootCompletedPhase    remediationPhase = "Reboot-Completed"
 	fencingCompletedPhase   remediationPhase = "Fencing-Completed"
+	verifyingRecoveryPhase  remediationPhase = "Verifying-Recovery"
 	unknownPhase            remediationPhase = "Unknown"
 )
 
+type phaseChangeReason string
+
+const (
+	phaseReasonRemediationStarted          phaseChangeReason = "RemediationStarted"
+	phaseReasonMaxConcurrentFencingReached phaseChangeReason = "MaxConcurrentFencingReached"
+	phaseReasonFencingAdmitted             phaseChangeReason = "FencingAdmitted"
+	phaseReasonBlackoutWindowActive        phaseChangeReason = "BlackoutWindowActive"
+	phaseReasonBlackoutWindowEnded         phaseChangeReason = "BlackoutWindowEnded"
+	phaseReasonNodeFenced                  phaseChangeReason = "NodeFenced"
+	phaseReasonNodeRebooted                phaseChangeReason = "NodeAssumedRebooted"
+	phaseReasonNodeBootIDChanged           phaseChangeReason = "NodeBootIDChanged"
+	phaseReasonResourcesRemoved            phaseChangeReason = "NodeResourcesRemoved"
+	phaseReasonNodeReadyAfterReboot        phaseChangeReason = "NodeReadyAfterReboot"
+	phaseReasonRecoveryVerificationStarted phaseChangeReason = "RecoveryVerificationStarted"
+)
+
 type UnreconcilableError struct {
 	msg string
 }
@@ -130,6 +239,27 @@ func (r *SelfNodeRemediationReconciler) WasLastSeenSnrMachine() bool {
 	return wasLastSeenSnrMachine
 }
 
+// GetRebootHoldReason returns why the node of the given snr must not reboot itself, even though it has a remediation,
+// or an empty string if nothing holds back its reboot. Peers use it for answering whether the node is healthy
+func GetRebootHoldReason(snr *v1alpha1.SelfNodeRemediation) string {
+	if snr.Spec.DryRun {
+		return "the remediation is a dry run"
+	}
+	if meta.IsStatusConditionTrue(snr.Status.Conditions, v1alpha1.PermanentlyFailedConditionType) {
+		return "the remediation was refused since the node is permanently failed"
+	}
+	if snr.Status.Phase != nil && remediationPhase(*snr.Status.Phase) == waitingPhase {
+		return "the remediation is waiting for the max concurrent fencing limit"
+	}
+	if snr.Status.Phase != nil && remediationPhase(*snr.Status.Phase) == postponedPhase {
+		return "the remediation is postponed by a blackout window"
+	}
+	if meta.IsStatusConditionTrue(snr.Status.Conditions, v1alpha1.ZoneFailureSuspectedConditionType) {
+		return "fencing is paused since a zone failure is suspected"
+	}
+	return ""
+}
+
 // SelfNodeRemediationReconciler reconciles a SelfNodeRemediation object
 type SelfNodeRemediationReconciler struct {
 	client.Client
@@ -147,17 +277,46 @@ type SelfNodeRemediationReconciler struct {
 	//see here: https://github.com/kubernetes/kubernetes/blob/7a0638da76cb9843def65708b661d2c6aa58ed5a/pkg/controller/podgc/gc_controller.go#L43-L47
 	RestoreNodeAfter time.Duration
 	reboot.SafeTimeCalculator
+	// admittedSnrs are the snrs whose fencing was admitted by this manager, since the cache might not have their
+	// finalizer yet when the next snr is admitted
+	admittedSnrs map[types.UID]bool
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SelfNodeRemediationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if _, isIndexed := podIndexers.LoadOrStore(mgr.GetFieldIndexer(), true); !isIndexed {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1.Pod{}, podNodeNameField, func(obj client.Object) []string {
+			return []string{obj.(*v1.Pod).Spec.NodeName}
+		}); err != nil {
+			return err
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.SelfNodeRemediation{}).
+		// changes to the config, e.g. of the blackout windows, might allow held back remediations to continue
+		Watches(&source.Kind{Type: &v1alpha1.SelfNodeRemediationConfig{}}, handler.EnqueueRequestsFromMapFunc(r.getAllSnrRequests)).
 		Complete(r)
 }
 
+// getAllSnrRequests returns a reconcile request for each existing snr
+func (r *SelfNodeRemediationReconciler) getAllSnrRequests(_ client.Object) []reconcile.Request {
+	snrList := &v1alpha1.SelfNodeRemediationList{}
+	if err := r.List(context.Background(), snrList); err != nil {
+		r.Log.Error(err, "failed to list SNRs")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, len(snrList.Items))
+	for i := range snrList.Items {
+		requests[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&snrList.Items[i])}
+	}
+	return requests
+}
+
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;delete;deletecollection
-//+kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments,verbs=get;list;watch;update;delete;deletecollection
+//+kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments,verbs=get;list;watch;update;patch;delete;deletecollection
 //+kubebuilder:rbac:groups=self-node-remediation.medik8s.io,resources=selfnoderemediationtemplates,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=self-node-remediation.medik8s.io,resources=selfnoderemediationtemplates/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=self-node-remediation.medik8s.io,resources=selfnoderemediationtemplates/finalizers,verbs=update
@@ -165,7 +324,9 @@ func (r *SelfNodeRemediationReconciler) SetupWithManager(mgr ctrl.Manager) error
 //+kubebuilder:rbac:groups=self-node-remediation.medik8s.io,resources=selfnoderemediations/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=self-node-remediation.medik8s.io,resources=selfnoderemediations/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=nodes/healthz;nodes/proxy,verbs=get
 //+kubebuilder:rbac:groups=machine.openshift.io,resources=machines,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=namespaces,verbs=list;get;watch
 
 func (r *SelfNodeRemediationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (returnResult ctrl.Result, returnErr error) {
@@ -193,6 +354,7 @@ func (r *SelfNodeRemediationReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationCreated, "Remediation started")
 
+	orgSnr := snr.DeepCopy()
 	defer func() {
 		if updateErr := r.updateSnrStatus(ctx, snr); updateErr != nil {
 			if apiErrors.IsConflict(updateErr) {
@@ -207,17 +369,37 @@ func (r *SelfNodeRemediationReconciler) Reconcile(ctx context.Context, req ctrl.
 			} else {
 				returnErr = utilerrors.NewAggregate([]error{updateErr, returnErr})
 			}
+			return
 		}
+		r.observeRemediationFinished(orgSnr, snr)
 	}()
 
 	if r.isStoppedByNHC(snr) {
 		msg := "SNR remediation was stopped by Node Healthcheck"
 		r.logger.Info(msg)
 		r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationStopped, msg)
+		r.clearRemediationInProgress(snr)
 		return ctrl.Result{}, r.updateConditions(remediationTimeoutByNHC, snr)
 	}
 
-	if r.getPhase(snr) != fencingCompletedPhase {
+	// a deleted snr is always cleaned up, so that pausing remediation doesn't keep fenced nodes unschedulable
+	if snr.DeletionTimestamp == nil {
+		isPaused, err := r.pauseIfConfigured(snr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if isPaused {
+			// changing the config triggers a reconcile, no need to requeue
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if meta.IsStatusConditionTrue(snr.Status.Conditions, v1alpha1.PermanentlyFailedConditionType) {
+		r.logger.Info("remediation was refused since the node is permanently failed")
+		return ctrl.Result{}, nil
+	}
+
+	if !r.isFencingCompleted(snr) && !r.isRemediationAborted(snr) {
 		if err := r.updateConditions(remediationStarted, snr); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -230,20 +412,108 @@ func (r *SelfNodeRemediationReconciler) Reconcile(ctx context.Context, req ctrl.
 	result := ctrl.Result{}
 	var err error
 
-	switch snr.Spec.RemediationStrategy {
+	strategy := r.getRemediationStrategy(snr)
+	switch strategy {
 	case v1alpha1.ResourceDeletionRemediationStrategy:
 		result, err = r.remediateWithResourceDeletion(snr)
 	case v1alpha1.OutOfServiceTaintRemediationStrategy:
 		result, err = r.remediateWithOutOfServiceTaint(snr)
+	case v1alpha1.NodeDeletionRemediationStrategy:
+		result, err = r.remediateWithNodeDeletion(snr)
+	case v1alpha1.RebootOnlyRemediationStrategy:
+		result, err = r.remediateWithRebootOnly(snr)
 	default:
 		//this should never happen since we enforce valid values with kubebuilder
 		err := errors.New("unsupported remediation strategy")
-		r.logger.Error(err, "Encountered unsupported remediation strategy. Please check template spec", "strategy", snr.Spec.RemediationStrategy)
+		r.logger.Error(err, "Encountered unsupported remediation strategy. Please check template spec", "strategy", strategy)
 	}
 
 	return result, r.updateSnrStatusLastError(snr, err)
 }
 
+// pauseIfConfigured returns true if remediation is paused by the config, in which case the snr doesn't advance
+// and has the Paused condition
+func (r *SelfNodeRemediationReconciler) pauseIfConfigured(snr *v1alpha1.SelfNodeRemediation) (bool, error) {
+	config, err := r.getConfig()
+	if err != nil {
+		return false, err
+	}
+
+	wasPaused := meta.IsStatusConditionTrue(snr.Status.Conditions, v1alpha1.PausedConditionType)
+	if config == nil || !config.Spec.Paused {
+		if wasPaused {
+			r.logger.Info("remediation was unpaused, resuming remediation")
+			meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+				Type:   v1alpha1.PausedConditionType,
+				Status: metav1.ConditionFalse,
+				Reason: remediationUnpausedReason,
+			})
+			r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationResumed, "Remediation process - remediation was unpaused, resuming remediation")
+		}
+		return false, nil
+	}
+
+	if !wasPaused {
+		r.logger.Info("remediation is paused by the config")
+		meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.PausedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  remediationPausedReason,
+			Message: "remediation is paused by the SelfNodeRemediationConfig",
+		})
+		r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationPaused, "Remediation process - remediation is paused by the SelfNodeRemediationConfig")
+	}
+	return true, nil
+}
+
+// clearRemediationInProgress clears the in-progress metric of the node remediated by the given snr
+func (r *SelfNodeRemediationReconciler) clearRemediationInProgress(snr *v1alpha1.SelfNodeRemediation) {
+	nodeName, err := r.getNodeNameOfSnr(snr)
+	if err != nil {
+		r.logger.Error(err, "failed to clear the remediation in progress metric, couldn't get the node name")
+		return
+	}
+	metrics.SetRemediationInProgress(nodeName, false)
+}
+
+// observeRemediationFinished counts the remediation if the given status update finished it.
+// Remediations are counted by the manager only, so that they aren't counted by the agent again, and dry runs aren't counted
+func (r *SelfNodeRemediationReconciler) observeRemediationFinished(org, snr *v1alpha1.SelfNodeRemediation) {
+	if r.IsAgent() || snr.Spec.DryRun {
+		return
+	}
+
+	succeeded := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.SucceededConditionType)
+	if succeeded == nil || succeeded.Status == metav1.ConditionUnknown {
+		return
+	}
+	if orgSucceeded := meta.FindStatusCondition(org.Status.Conditions, v1alpha1.SucceededConditionType); orgSucceeded != nil &&
+		orgSucceeded.Status == succeeded.Status && orgSucceeded.Reason == succeeded.Reason {
+		return
+	}
+	metrics.ObserveRemediationFinished(string(r.getRemediationStrategy(snr)), succeeded.Reason)
+}
+
+// getRemediationStrategy returns the strategy used for the given snr.
+// The strategy is recorded in the snr status on the first call, which is also when the Automatic strategy is resolved
+func (r *SelfNodeRemediationReconciler) getRemediationStrategy(snr *v1alpha1.SelfNodeRemediation) v1alpha1.RemediationStrategyType {
+	if snr.Status.RemediationStrategy != "" {
+		return snr.Status.RemediationStrategy
+	}
+
+	strategy := snr.Spec.RemediationStrategy
+	if strategy == v1alpha1.AutomaticRemediationStrategy {
+		if utils.IsOutOfServiceTaintSupported {
+			strategy = v1alpha1.OutOfServiceTaintRemediationStrategy
+		} else {
+			strategy = v1alpha1.ResourceDeletionRemediationStrategy
+		}
+		r.logger.Info("resolved automatic remediation strategy", "strategy", strategy)
+	}
+	snr.Status.RemediationStrategy = strategy
+	return strategy
+}
+
 func (r *SelfNodeRemediationReconciler) updateConditions(processingTypeReason processingChangeReason, snr *v1alpha1.SelfNodeRemediation) error {
 	var processingConditionStatus, succeededConditionStatus metav1.ConditionStatus
 	switch processingTypeReason {
@@ -256,7 +526,7 @@ func (r *SelfNodeRemediationReconciler) updateConditions(processingTypeReason pr
 	case remediationTimeoutByNHC:
 		processingConditionStatus = metav1.ConditionFalse
 		succeededConditionStatus = metav1.ConditionFalse
-	case remediationFinishedNodeNotFound:
+	case remediationFinishedNodeNotFound, remediationPermanentlyFailed, remediationAborted, remediationRecoveryTimedOut:
 		processingConditionStatus = metav1.ConditionFalse
 		succeededConditionStatus = metav1.ConditionFalse
 	default:
@@ -302,22 +572,251 @@ func (r *SelfNodeRemediationReconciler) getPhase(snr *v1alpha1.SelfNodeRemediati
 	}
 	phase := remediationPhase(*snr.Status.Phase)
 	switch phase {
-	case preRebootCompletedPhase, rebootCompletedPhase, fencingCompletedPhase:
+	case waitingPhase, postponedPhase, fencingStartedPhase, preRebootCompletedPhase, rebootCompletedPhase, fencingCompletedPhase, verifyingRecoveryPhase:
 		return phase
 	default:
 		return unknownPhase
 	}
 }
 
+// isFencingCompleted returns true if fencing the node was completed, which is also the case while verifying its recovery
+func (r *SelfNodeRemediationReconciler) isFencingCompleted(snr *v1alpha1.SelfNodeRemediation) bool {
+	phase := r.getPhase(snr)
+	return phase == fencingCompletedPhase || phase == verifyingRecoveryPhase
+}
+
+// isRemediationAborted returns true if the reboot wasn't approved in time, or if the node didn't recover in time
+// after it was rebooted by the RebootOnly remediation strategy, and the remediation was aborted
+func (r *SelfNodeRemediationReconciler) isRemediationAborted(snr *v1alpha1.SelfNodeRemediation) bool {
+	return meta.IsStatusConditionFalse(snr.Status.Conditions, v1alpha1.RebootApprovedConditionType) ||
+		(r.getPhase(snr) == rebootCompletedPhase && meta.IsStatusConditionFalse(snr.Status.Conditions, v1alpha1.NodeRecoveredConditionType))
+}
+
+// setPhase moves the snr to the given phase, records the time spent in the previous phase and adds the change to the timeline
+func (r *SelfNodeRemediationReconciler) setPhase(snr *v1alpha1.SelfNodeRemediation, phase remediationPhase, reason phaseChangeReason) {
+	r.observePhaseDuration(snr)
+
+	phaseVal := string(phase)
+	snr.Status.Phase = &phaseVal
+	r.addTimelineEntry(snr, phase, reason)
+}
+
+// addTimelineEntry appends a phase transition made by this reconciler to the snr timeline
+func (r *SelfNodeRemediationReconciler) addTimelineEntry(snr *v1alpha1.SelfNodeRemediation, phase remediationPhase, reason phaseChangeReason) {
+	snr.Status.Timeline = append(snr.Status.Timeline, v1alpha1.PhaseTransition{
+		Phase:          string(phase),
+		TransitionTime: metav1.Now(),
+		Actor:          r.getActor(),
+		Reason:         string(reason),
+	})
+}
+
+// getActor returns the identity this reconciler uses in the snr timeline
+func (r *SelfNodeRemediationReconciler) getActor() string {
+	if r.IsAgent() {
+		return fmt.Sprintf("agent/%s", r.MyNodeName)
+	}
+	return "manager"
+}
+
+// observePhaseDuration records the time spent in the current phase, based on the snr timeline.
+// Only the manager records it, so that phases which are left by the agent aren't recorded twice
+func (r *SelfNodeRemediationReconciler) observePhaseDuration(snr *v1alpha1.SelfNodeRemediation) {
+	if r.IsAgent() {
+		return
+	}
+	phase := r.getPhase(snr)
+
+	var startTime time.Time
+	if timelineLen := len(snr.Status.Timeline); timelineLen > 0 && snr.Status.Timeline[timelineLen-1].Phase == string(phase) {
+		startTime = snr.Status.Timeline[timelineLen-1].TransitionTime.Time
+	} else if phase == fencingStartedPhase {
+		startTime = snr.CreationTimestamp.Time
+	} else {
+		return
+	}
+	metrics.ObservePhaseDuration(string(phase), time.Since(startTime))
+}
+
 func (r *SelfNodeRemediationReconciler) remediateWithResourceDeletion(snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
 	return r.remediateWithResourceRemoval(snr, r.deleteResourcesWrapper)
 }
 
 // deleteResourcesWrapper returns a 'zero' time and nil if it completes to delete node resources successfully
 // if not, it will return a 'zero' time and non-nil error, which means exponential backoff is triggered
-// SelfNodeRemediation is only used in order to match method signature required by remediateWithResourceRemoval
-func (r *SelfNodeRemediationReconciler) deleteResourcesWrapper(node *v1.Node, _ *v1alpha1.SelfNodeRemediation) (time.Duration, error) {
-	return 0, resources.DeletePods(context.Background(), r.Client, node.Name)
+func (r *SelfNodeRemediationReconciler) deleteResourcesWrapper(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (time.Duration, error) {
+	if err := r.deletePods(node, snr); err != nil {
+		return 0, err
+	}
+	return r.deleteVolumeAttachments(node, snr)
+}
+
+// deleteVolumeAttachments deletes the VolumeAttachments of the node, so that its volumes can be attached to other nodes,
+// and returns the time to wait until they are gone. The finalizers of a VolumeAttachment are removed if the finalizer
+// policy of its CSI driver is Remove, otherwise the CSI driver is expected to remove them after detaching the volume
+func (r *SelfNodeRemediationReconciler) deleteVolumeAttachments(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (time.Duration, error) {
+	volumeAttachments := &storagev1.VolumeAttachmentList{}
+	if err := r.Client.List(context.Background(), volumeAttachments); err != nil {
+		r.logger.Error(err, "failed to get volumeAttachments list")
+		return 0, err
+	}
+
+	var finalizerPolicies map[string]v1alpha1.VolumeAttachmentFinalizerPolicyType
+	var pendingVolumeAttachments []string
+	for i := range volumeAttachments.Items {
+		va := &volumeAttachments.Items[i]
+		if va.Spec.NodeName != node.Name {
+			continue
+		}
+
+		if va.DeletionTimestamp == nil {
+			if err := r.Client.Delete(context.Background(), va); err != nil {
+				if apiErrors.IsNotFound(err) {
+					continue
+				}
+				r.logger.Error(err, "failed to delete volumeAttachment", "name", va.Name)
+				return 0, err
+			}
+			r.logger.Info("volumeAttachment deleted", "name", va.Name, "attacher", va.Spec.Attacher)
+		}
+
+		if len(va.Finalizers) == 0 {
+			continue
+		}
+
+		if finalizerPolicies == nil {
+			var err error
+			if finalizerPolicies, err = r.getVolumeAttachmentFinalizerPolicies(); err != nil {
+				return 0, err
+			}
+		}
+		if finalizerPolicies[va.Spec.Attacher] != v1alpha1.RemoveVolumeAttachmentFinalizerPolicy {
+			pendingVolumeAttachments = append(pendingVolumeAttachments, va.Name)
+			continue
+		}
+
+		// the node was already fenced, so it's safe to force detach the volume
+		patch := client.MergeFrom(va.DeepCopy())
+		va.Finalizers = nil
+		if err := r.Client.Patch(context.Background(), va, patch); err != nil && !apiErrors.IsNotFound(err) {
+			r.logger.Error(err, "failed to remove volumeAttachment finalizers", "name", va.Name)
+			return 0, err
+		}
+		r.logger.Info("volumeAttachment finalizers removed", "name", va.Name, "attacher", va.Spec.Attacher)
+	}
+
+	if len(pendingVolumeAttachments) == 0 {
+		return 0, nil
+	}
+
+	r.logger.Info("waiting for deleting volumeAttachments", "names", pendingVolumeAttachments)
+	isExpired, timeLeft := r.isResourceDeletionExpired(snr)
+	if !isExpired {
+		return timeLeft, nil
+	}
+	// if the timer is expired, exponential backoff is triggered
+	return 0, fmt.Errorf("volumeAttachments %v of the node weren't deleted yet", pendingVolumeAttachments)
+}
+
+// getVolumeAttachmentFinalizerPolicies returns the configured VolumeAttachment finalizer policies by CSI driver name
+func (r *SelfNodeRemediationReconciler) getVolumeAttachmentFinalizerPolicies() (map[string]v1alpha1.VolumeAttachmentFinalizerPolicyType, error) {
+	policies := map[string]v1alpha1.VolumeAttachmentFinalizerPolicyType{}
+	config, err := r.getConfig()
+	if err != nil || config == nil {
+		return policies, err
+	}
+	for _, policy := range config.Spec.VolumeAttachmentFinalizerPolicies {
+		policies[policy.Driver] = policy.Policy
+	}
+	return policies, nil
+}
+
+// deletePods deletes the pods of the node by their deletion priority, highest first.
+// Pods which are excluded from deletion are skipped, and the result for each pod is reported in the snr status
+func (r *SelfNodeRemediationReconciler) deletePods(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) error {
+	pods, err := r.getNodePods(node.Name)
+	if err != nil {
+		return err
+	}
+
+	type podToDelete struct {
+		pod      *v1.Pod
+		deletion v1alpha1.PodDeletion
+	}
+	podsToDelete := make([]podToDelete, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		podsToDelete[i] = podToDelete{
+			pod:      pod,
+			deletion: v1alpha1.PodDeletion{Namespace: pod.Namespace, Name: pod.Name, Priority: r.getPodDeletionPriority(pod)},
+		}
+	}
+	sort.SliceStable(podsToDelete, func(i, j int) bool {
+		return podsToDelete[i].deletion.Priority > podsToDelete[j].deletion.Priority
+	})
+
+	r.logger.Info("starting to delete pods", "node name", node.Name)
+	var errs []error
+	for i, toDelete := range podsToDelete {
+		pod, podDeletion := toDelete.pod, toDelete.deletion
+		// pods of a lower priority are deleted only after all pods of a higher priority were deleted
+		if len(errs) > 0 && podDeletion.Priority < podsToDelete[i-1].deletion.Priority {
+			break
+		}
+
+		if pod.Annotations[v1alpha1.ExcludeFromDeletionAnnotation] == "true" {
+			podDeletion.Result = v1alpha1.PodDeletionExcluded
+		} else if err = r.Client.Delete(context.Background(), pod, client.GracePeriodSeconds(0), client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apiErrors.IsNotFound(err) {
+			r.logger.Error(err, "failed to delete pod", "pod name", pod.Name, "namespace", pod.Namespace)
+			podDeletion.Result = v1alpha1.PodDeletionFailed
+			podDeletion.Message = err.Error()
+			errs = append(errs, err)
+		} else {
+			podDeletion.Result = v1alpha1.PodDeleted
+		}
+		setPodDeletion(snr, podDeletion)
+	}
+
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+	r.logger.Info("done deleting pods", "node name", node.Name)
+	return nil
+}
+
+// getNodePods returns the pods bound to the given node
+func (r *SelfNodeRemediationReconciler) getNodePods(nodeName string) ([]v1.Pod, error) {
+	pods := &v1.PodList{}
+	if err := r.Client.List(context.Background(), pods, client.MatchingFields{podNodeNameField: nodeName}); err != nil {
+		r.logger.Error(err, "failed to list pods", "node name", nodeName)
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// getPodDeletionPriority returns the deletion priority from the pod annotation, or 0 if it isn't set or isn't valid
+func (r *SelfNodeRemediationReconciler) getPodDeletionPriority(pod *v1.Pod) int {
+	priorityVal, exists := pod.Annotations[v1alpha1.DeletionPriorityAnnotation]
+	if !exists {
+		return 0
+	}
+	priority, err := strconv.Atoi(priorityVal)
+	if err != nil {
+		r.logger.Error(err, "invalid pod deletion priority, using the default priority", "pod name", pod.Name, "namespace", pod.Namespace, "annotation value", priorityVal)
+		return 0
+	}
+	return priority
+}
+
+// setPodDeletion records the deletion result of a pod in the snr status, replacing a previous result of the same pod
+func setPodDeletion(snr *v1alpha1.SelfNodeRemediation, podDeletion v1alpha1.PodDeletion) {
+	for i := range snr.Status.PodDeletions {
+		if snr.Status.PodDeletions[i].Namespace == podDeletion.Namespace && snr.Status.PodDeletions[i].Name == podDeletion.Name {
+			snr.Status.PodDeletions[i] = podDeletion
+			return
+		}
+	}
+	snr.Status.PodDeletions = append(snr.Status.PodDeletions, podDeletion)
 }
 
 func (r *SelfNodeRemediationReconciler) remediateWithOutOfServiceTaint(snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
@@ -340,6 +839,11 @@ func (r *SelfNodeRemediationReconciler) useOutOfServiceTaint(node *v1.Node, snr
 		return 0, errors.New("Not ready to delete out-of-service taint")
 	}
 
+	if r.hadTaintBeforeRemediation(snr, OutOfServiceTaint) {
+		r.logger.Info("the node had the out-of-service taint before it was fenced, keeping it", "node name", node.Name)
+		return 0, nil
+	}
+
 	if err := r.removeOutOfServiceTaint(node); err != nil {
 		return 0, err
 	}
@@ -347,10 +851,165 @@ func (r *SelfNodeRemediationReconciler) useOutOfServiceTaint(node *v1.Node, snr
 	return 0, nil
 }
 
+func (r *SelfNodeRemediationReconciler) remediateWithNodeDeletion(snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	return r.remediateWithResourceRemoval(snr, r.deleteNode)
+}
+
+func (r *SelfNodeRemediationReconciler) remediateWithRebootOnly(snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	return r.remediateWithResourceRemoval(snr, r.waitForNodeReboot)
+}
+
+// waitForNodeReboot keeps the workloads of the node in place, and waits for the node to be Ready after it was rebooted.
+// The remediation is escalated while waiting, if configured, and fails when the node doesn't recover in time
+func (r *SelfNodeRemediationReconciler) waitForNodeReboot(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (time.Duration, error) {
+	if meta.IsStatusConditionFalse(snr.Status.Conditions, v1alpha1.NodeRecoveredConditionType) {
+		return 0, &UnreconcilableError{msg: "node didn't recover after it was rebooted"}
+	}
+
+	isReady, isRebooted := r.isNodeReadyAfterReboot(node, snr)
+	if isReady && isRebooted {
+		r.Recorder.Event(node, eventTypeNormal, eventReasonNodeRecovered, "Remediation process - node is Ready after it was rebooted")
+		return 0, nil
+	}
+
+	requeueAfter := recoveryVerificationRequeueInterval
+	if snr.Spec.Escalation != nil {
+		escalationResult, err := r.escalateIfNodeNotReady(node, snr)
+		if err != nil {
+			return 0, err
+		}
+		if escalationResult.RequeueAfter > 0 && escalationResult.RequeueAfter < requeueAfter {
+			requeueAfter = escalationResult.RequeueAfter
+		}
+	}
+
+	timeout := defaultRebootOnlyRecoveryTimeout
+	if snr.Spec.RecoveryVerificationTimeout != nil {
+		timeout = snr.Spec.RecoveryVerificationTimeout.Duration
+	}
+	if time.Until(r.getFencingCompletedTime(snr).Add(timeout)) > 0 {
+		r.logger.Info("waiting for the node to be Ready after it was rebooted", "node name", node.Name, "Ready", isReady, "rebooted", isRebooted)
+		return requeueAfter, nil
+	}
+
+	msg := fmt.Sprintf("node isn't Ready %s after it was assumed to be rebooted, Ready: %t, rebooted: %t", timeout, isReady, isRebooted)
+	r.logger.Info("node didn't recover after it was rebooted", "node name", node.Name, "Ready", isReady, "rebooted", isRebooted)
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.NodeRecoveredConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "RecoveryVerificationTimedOut",
+		Message: msg,
+	})
+	r.Recorder.Event(snr, eventTypeWarning, eventReasonNodeNotRecovered, "Remediation process - "+msg)
+	metrics.SetRemediationInProgress(node.Name, false)
+	if err := r.updateConditions(remediationRecoveryTimedOut, snr); err != nil {
+		return 0, err
+	}
+	return 0, &UnreconcilableError{msg: msg}
+}
+
+// deleteNode deletes the node, so that cloud controllers and CSI attachers fully reset, and waits for its kubelet to re-register it.
+// If that doesn't happen within RestoreNodeAfter, the node is re-created from the backup in the snr status
+func (r *SelfNodeRemediationReconciler) deleteNode(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (time.Duration, error) {
+	if snr.Status.NodeDeletionTime == nil {
+		if err := r.Client.Delete(context.Background(), node); err != nil && !apiErrors.IsNotFound(err) {
+			r.logger.Error(err, "failed to delete node", "node name", node.Name)
+			return 0, err
+		}
+		now := metav1.Now()
+		snr.Status.NodeDeletionTime = &now
+		r.logger.Info("node deleted", "node name", node.Name)
+		r.Recorder.Event(snr, eventTypeNormal, eventReasonDeleteNode, "Remediation process - unhealthy node deleted")
+		return r.RestoreNodeAfter, nil
+	}
+
+	currentNode := &v1.Node{}
+	if err := r.Get(context.Background(), client.ObjectKey{Name: node.Name}, currentNode); err == nil {
+		if currentNode.DeletionTimestamp != nil {
+			// the deleted node still exists
+			return time.Second, nil
+		}
+		r.logger.Info("node was re-created", "node name", node.Name)
+		return 0, nil
+	} else if !apiErrors.IsNotFound(err) {
+		r.logger.Error(err, "failed to get node", "node name", node.Name)
+		return 0, err
+	}
+
+	if timeLeft := time.Until(snr.Status.NodeDeletionTime.Add(r.RestoreNodeAfter)); timeLeft > 0 {
+		return timeLeft, nil
+	}
+
+	if snr.Status.NodeBackup == nil {
+		// this should never happen since the backup is taken before fencing the node
+		return 0, &UnreconcilableError{msg: "failed to re-create the node, node backup is missing"}
+	}
+	if _, err := r.restoreNode(nodeFromBackup(snr.Status.NodeBackup)); err != nil {
+		return 0, err
+	}
+	r.Recorder.Event(snr, eventTypeNormal, eventReasonRestoreNode, "Remediation process - unhealthy node re-created from backup")
+	return 0, nil
+}
+
+// backupNode stores the node spec, labels and annotations in the snr status, for re-creating the node after deleting it
+// Annotations which describe the node object, and are set again by the kubelet and the node controllers for the
+// re-created node, aren't part of the backup
+func (r *SelfNodeRemediationReconciler) backupNode(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) {
+	backup := &v1alpha1.NodeBackup{
+		Name:   node.Name,
+		Labels: make(map[string]string, len(node.Labels)),
+		Spec:   *node.Spec.DeepCopy(),
+	}
+	for key, value := range node.Labels {
+		backup.Labels[key] = value
+	}
+	for key, value := range node.Annotations {
+		if isNodeSpecificAnnotation(key) {
+			continue
+		}
+		if backup.Annotations == nil {
+			backup.Annotations = map[string]string{}
+		}
+		backup.Annotations[key] = value
+	}
+	snr.Status.NodeBackup = backup
+}
+
+// isNodeSpecificAnnotation returns true for annotations which describe the node object rather than the machine
+func isNodeSpecificAnnotation(key string) bool {
+	for _, prefix := range nodeSpecificAnnotationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeFromBackup returns a node, which is built from the given node backup
+func nodeFromBackup(backup *v1alpha1.NodeBackup) *v1.Node {
+	backup = backup.DeepCopy()
+	return &v1.Node{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Node",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        backup.Name,
+			Labels:      backup.Labels,
+			Annotations: backup.Annotations,
+		},
+		Spec: backup.Spec,
+	}
+}
+
 type removeNodeResources func(*v1.Node, *v1alpha1.SelfNodeRemediation) (time.Duration, error)
 
 func (r *SelfNodeRemediationReconciler) remediateWithResourceRemoval(snr *v1alpha1.SelfNodeRemediation, rmNodeResources removeNodeResources) (ctrl.Result, error) {
 	node, err := r.getNodeFromSnr(snr)
+	if err != nil && apiErrors.IsNotFound(err) && snr.Status.NodeDeletionTime != nil && snr.Status.NodeBackup != nil {
+		// the node was deleted by the NodeDeletion remediation strategy, and is going to be re-created
+		node, err = nodeFromBackup(snr.Status.NodeBackup), nil
+	}
 	if err != nil {
 		if apiErrors.IsNotFound(err) {
 			r.logger.Info("couldn't find node matching remediation", "node name", snr.Name)
@@ -358,22 +1017,27 @@ func (r *SelfNodeRemediationReconciler) remediateWithResourceRemoval(snr *v1alph
 				return ctrl.Result{}, err
 			}
 			r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationStopped, "couldn't find node matching remediation")
+			r.clearRemediationInProgress(snr)
 			return ctrl.Result{}, nil
 		}
 		r.logger.Error(err, "failed to get node", "node name", snr.Name)
 		return ctrl.Result{}, err
 	}
 
+	if !snr.Spec.DryRun {
+		metrics.SetRemediationInProgress(node.Name, true)
+	}
+
 	result := ctrl.Result{}
 	phase := r.getPhase(snr)
 	switch phase {
-	case fencingStartedPhase:
+	case waitingPhase, postponedPhase, fencingStartedPhase:
 		result, err = r.handleFencingStartedPhase(node, snr)
 	case preRebootCompletedPhase:
 		result, err = r.handlePreRebootCompletedPhase(node, snr)
 	case rebootCompletedPhase:
 		result, err = r.handleRebootCompletedPhase(node, snr, rmNodeResources)
-	case fencingCompletedPhase:
+	case fencingCompletedPhase, verifyingRecoveryPhase:
 		result, err = r.handleFencingCompletedPhase(node, snr)
 	default:
 		//this should never happen since we enforce valid values with kubebuilder
@@ -384,74 +1048,794 @@ func (r *SelfNodeRemediationReconciler) remediateWithResourceRemoval(snr *v1alph
 }
 
 func (r *SelfNodeRemediationReconciler) handleFencingStartedPhase(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	// the finalizer is the first change made before fencing the node, so a snr without it wasn't admitted yet.
+	// A dry run doesn't fence the node, so it doesn't need to be admitted
+	if !snr.Spec.DryRun && !controllerutil.ContainsFinalizer(snr, SNRFinalizer) {
+		if r.IsAgent() {
+			// only the manager admits fencing, so that the reconciles of the manager and the agents can't exceed the
+			// max concurrent fencing limit together. Adding the finalizer after admission triggers a reconcile
+			r.logger.Info("waiting for the manager to admit fencing the node", "node name", node.Name)
+			return ctrl.Result{}, nil
+		}
+
+		isRefused, err := r.refuseIfInRebootLoop(node, snr)
+		if err != nil || isRefused {
+			return ctrl.Result{}, err
+		}
+
+		isPostponed, requeueAfter, err := r.postponeInBlackoutWindow(node, snr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if isPostponed {
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
+
+		isPaused, err := r.pauseOnSuspectedZoneFailure(node, snr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if isPaused {
+			return ctrl.Result{RequeueAfter: waitingForFencingRequeueInterval}, nil
+		}
+
+		isAdmitted, err := r.admitFencing(snr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !isAdmitted {
+			return ctrl.Result{RequeueAfter: waitingForFencingRequeueInterval}, nil
+		}
+
+		if err = r.addToRemediationHistory(node, snr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 	return r.prepareReboot(node, snr)
 }
 
-func (r *SelfNodeRemediationReconciler) prepareReboot(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
-	r.logger.Info("pre-reboot not completed yet, prepare for rebooting")
-	if !r.isNodeRebootCapable(node) {
-		//use err to trigger exponential backoff
-		return ctrl.Result{}, errors.New("Node is not capable to reboot itself")
+// refuseIfInRebootLoop returns true if the node was remediated too often within the configured reboot loop threshold.
+// In that case the remediation is refused with the PermanentlyFailed condition, and the node is left cordoned for a human
+func (r *SelfNodeRemediationReconciler) refuseIfInRebootLoop(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (bool, error) {
+	config, err := r.getConfig()
+	if err != nil || config == nil || config.Spec.RebootLoopThreshold == nil {
+		return false, err
 	}
+	threshold := config.Spec.RebootLoopThreshold
 
-	if !controllerutil.ContainsFinalizer(snr, SNRFinalizer) {
-		return r.addFinalizer(snr)
+	recentRemediations := 0
+	since := time.Now().Add(-threshold.TimeWindow.Duration)
+	for _, remediationTime := range r.getRemediationHistory(node) {
+		// the history contains the current remediation if it was already added by a previous reconcile
+		if remediationTime.After(since) && !remediationTime.Equal(snr.CreationTimestamp.Time) {
+			recentRemediations++
+		}
 	}
-
-	if err := r.addNoExecuteTaint(node); err != nil {
-		return ctrl.Result{}, err
+	if recentRemediations < threshold.MaxRemediations {
+		return false, nil
 	}
 
-	if !node.Spec.Unschedulable || !utils.TaintExists(node.Spec.Taints, NodeUnschedulableTaint) {
-		return r.markNodeAsUnschedulable(node)
-	}
+	msg := fmt.Sprintf("node was already remediated %d times within %s, which exceeds the reboot loop threshold. Leaving the node cordoned for manual investigation", recentRemediations, threshold.TimeWindow.Duration)
+	r.logger.Info("reboot loop detected, refusing to remediate the node", "node name", node.Name, "recent remediations", recentRemediations, "time window", threshold.TimeWindow.Duration)
 
-	if snr.Status.TimeAssumedRebooted.IsZero() {
-		r.updateTimeAssumedRebooted(node, snr)
+	if !node.Spec.Unschedulable {
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Spec.Unschedulable = true
+		if err = r.Client.Patch(context.Background(), node, patch); err != nil {
+			r.logger.Error(err, "failed to cordon node", "node name", node.Name)
+			return false, err
+		}
 	}
 
-	preRebootCompleted := string(preRebootCompletedPhase)
-	snr.Status.Phase = &preRebootCompleted
-
-	return ctrl.Result{}, nil
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.PermanentlyFailedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  rebootLoopDetectedReason,
+		Message: msg,
+	})
+	if err = r.updateConditions(remediationPermanentlyFailed, snr); err != nil {
+		return false, err
+	}
+	r.Recorder.Event(snr, eventTypeWarning, eventReasonRemediationRefused, "Remediation process - remediation refused: "+msg)
+	metrics.SetRemediationInProgress(node.Name, false)
+	return true, nil
 }
 
-func (r *SelfNodeRemediationReconciler) handlePreRebootCompletedPhase(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
-	return r.rebootNode(node, snr)
-}
+// getRemediationHistory returns the remediation times from the remediation history annotation of the node, ignoring invalid values
+func (r *SelfNodeRemediationReconciler) getRemediationHistory(node *v1.Node) []time.Time {
+	historyVal := node.Annotations[v1alpha1.RemediationHistoryAnnotation]
+	if historyVal == "" {
+		return nil
+	}
 
-func (r *SelfNodeRemediationReconciler) rebootNode(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
-	r.logger.Info("node reboot not completed yet, start rebooting")
-	if r.MyNodeName == node.Name {
-		// we have a problem on this node, reboot!
-		return r.rebootIfNeeded(snr, node)
+	var history []time.Time
+	for _, timeVal := range strings.Split(historyVal, ",") {
+		remediationTime, err := time.Parse(time.RFC3339, timeVal)
+		if err != nil {
+			r.logger.Error(err, "ignoring invalid remediation time in the remediation history of the node", "node name", node.Name, "value", timeVal)
+			continue
+		}
+		history = append(history, remediationTime)
 	}
+	return history
+}
 
-	wasRebooted, timeLeft := r.wasNodeRebooted(snr)
+// addToRemediationHistory adds the creation time of the snr, which identifies the remediation, to the remediation history annotation of the node
+func (r *SelfNodeRemediationReconciler) addToRemediationHistory(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) error {
+	history := r.getRemediationHistory(node)
+	for _, remediationTime := range history {
+		if remediationTime.Equal(snr.CreationTimestamp.Time) {
+			return nil
+		}
+	}
+
+	history = append(history, snr.CreationTimestamp.Time)
+	if len(history) > maxRemediationHistoryLength {
+		history = history[len(history)-maxRemediationHistoryLength:]
+	}
+	timeVals := make([]string, len(history))
+	for i, remediationTime := range history {
+		timeVals[i] = remediationTime.UTC().Format(time.RFC3339)
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[v1alpha1.RemediationHistoryAnnotation] = strings.Join(timeVals, ",")
+	if err := r.Client.Patch(context.Background(), node, patch); err != nil {
+		r.logger.Error(err, "failed to update the remediation history of the node", "node name", node.Name)
+		return err
+	}
+	return nil
+}
+
+// postponeInBlackoutWindow moves the snr to the Postponed phase and returns true while a blackout window which applies to
+// the node is active, together with the time to check again. It resumes a postponed snr once no such window is active
+func (r *SelfNodeRemediationReconciler) postponeInBlackoutWindow(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (bool, time.Duration, error) {
+	window, windowEnd, err := r.getActiveBlackoutWindow(node)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if window == nil {
+		if r.getPhase(snr) == postponedPhase {
+			r.logger.Info("blackout window ended, resuming remediation", "node name", node.Name)
+			r.setPhase(snr, fencingStartedPhase, phaseReasonBlackoutWindowEnded)
+			meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+				Type:   v1alpha1.PostponedConditionType,
+				Status: metav1.ConditionFalse,
+				Reason: string(phaseReasonBlackoutWindowEnded),
+			})
+			r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationResumed, "Remediation process - blackout window ended, resuming remediation")
+		}
+		return false, 0, nil
+	}
+
+	msg := fmt.Sprintf("blackout window %s is active until %s", window.Name, windowEnd.Format(time.RFC3339))
+	if r.getPhase(snr) != postponedPhase {
+		r.logger.Info("blackout window is active, postponing remediation", "node name", node.Name, "blackout window", window.Name, "window end", windowEnd)
+		r.setPhase(snr, postponedPhase, phaseReasonBlackoutWindowActive)
+		r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationPostponed, "Remediation process - postponed until the blackout window ends: "+msg)
+	}
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.PostponedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  string(phaseReasonBlackoutWindowActive),
+		Message: msg,
+	})
+
+	requeueAfter := time.Until(windowEnd)
+	if requeueAfter > postponedRequeueInterval {
+		requeueAfter = postponedRequeueInterval
+	}
+	return true, requeueAfter, nil
+}
+
+// getActiveBlackoutWindow returns the active blackout window which applies to the node and ends last, and the time it ends,
+// or nil if there is no such window
+func (r *SelfNodeRemediationReconciler) getActiveBlackoutWindow(node *v1.Node) (*v1alpha1.BlackoutWindow, time.Time, error) {
+	config, err := r.getConfig()
+	if err != nil || config == nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now().UTC()
+	var activeWindow *v1alpha1.BlackoutWindow
+	var activeWindowEnd time.Time
+	for i := range config.Spec.BlackoutWindows {
+		window := &config.Spec.BlackoutWindows[i]
+		selector, err := metav1.LabelSelectorAsSelector(window.NodeSelector)
+		if err != nil {
+			r.logger.Error(err, "invalid node selector of blackout window", "blackout window", window.Name)
+			return nil, time.Time{}, err
+		}
+		// a nil selector selects nothing, while a window without a selector applies to all nodes
+		if window.NodeSelector != nil && !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+
+		schedule, err := utils.ParseCronSchedule(window.Schedule)
+		if err != nil {
+			r.logger.Error(err, "invalid schedule of blackout window", "blackout window", window.Name)
+			return nil, time.Time{}, err
+		}
+		windowStart, isActive := schedule.LastActivation(now, window.Duration.Duration)
+		if !isActive {
+			continue
+		}
+		if windowEnd := windowStart.Add(window.Duration.Duration); activeWindow == nil || windowEnd.After(activeWindowEnd) {
+			activeWindow, activeWindowEnd = window, windowEnd
+		}
+	}
+	return activeWindow, activeWindowEnd, nil
+}
+
+// pauseOnSuspectedZoneFailure returns true if fencing the node is paused, because a large share of the nodes in its zone
+// need remediation at the same time. Only the manager detects zone failures, since it sees all remediations,
+// the agent waits for its verdict in the ZoneFailureSuspected condition
+func (r *SelfNodeRemediationReconciler) pauseOnSuspectedZoneFailure(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (bool, error) {
+	config, err := r.getConfig()
+	if err != nil || config == nil || config.Spec.ZoneFailureDetection == nil {
+		return false, err
+	}
+	zone := node.Labels[v1.LabelTopologyZone]
+	if zone == "" {
+		return false, nil
+	}
+
+	if r.IsAgent() {
+		zoneFailureCond := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.ZoneFailureSuspectedConditionType)
+		return zoneFailureCond == nil || zoneFailureCond.Status == metav1.ConditionTrue, nil
+	}
+
+	isSuspected, msg, err := r.isZoneFailureSuspected(zone, snr, config.Spec.ZoneFailureDetection)
+	if err != nil {
+		return false, err
+	}
+
+	wasSuspected := meta.IsStatusConditionTrue(snr.Status.Conditions, v1alpha1.ZoneFailureSuspectedConditionType)
+	if !isSuspected {
+		if wasSuspected {
+			r.logger.Info("zone failure no longer suspected, resuming remediation", "node name", node.Name, "zone", zone)
+			r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationResumed, "Remediation process - zone failure no longer suspected, resuming remediation")
+		}
+		meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.ZoneFailureSuspectedConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  zoneFailureNotDetectedReason,
+			Message: msg,
+		})
+		return false, nil
+	}
+
+	if !wasSuspected {
+		r.logger.Info("zone failure suspected, pausing fencing", "node name", node.Name, "zone", zone)
+		r.Recorder.Event(snr, eventTypeWarning, eventReasonZoneFailureSuspected, "Remediation process - fencing paused: "+msg)
+	}
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ZoneFailureSuspectedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  zoneFailureDetectedReason,
+		Message: msg,
+	})
+	return true, nil
+}
+
+// isZoneFailureSuspected returns true if the share of the zone's nodes, which have remediations created within the
+// time window of the given snr, reached the threshold. The returned message describes the share of unhealthy nodes
+func (r *SelfNodeRemediationReconciler) isZoneFailureSuspected(zone string, snr *v1alpha1.SelfNodeRemediation, detection *v1alpha1.ZoneFailureDetection) (bool, string, error) {
+	zoneNodes := &v1.NodeList{}
+	if err := r.Client.List(context.Background(), zoneNodes, client.MatchingLabels{v1.LabelTopologyZone: zone}); err != nil {
+		r.logger.Error(err, "failed to list the nodes of the zone", "zone", zone)
+		return false, "", err
+	}
+	if len(zoneNodes.Items) == 0 {
+		return false, "", nil
+	}
+	zoneNodeNames := make(map[string]bool, len(zoneNodes.Items))
+	for _, zoneNode := range zoneNodes.Items {
+		zoneNodeNames[zoneNode.Name] = true
+	}
+
+	snrList := &v1alpha1.SelfNodeRemediationList{}
+	if err := r.Client.List(context.Background(), snrList); err != nil {
+		r.logger.Error(err, "failed to list SNRs")
+		return false, "", err
+	}
+
+	unhealthyNodeNames := map[string]bool{}
+	for i := range snrList.Items {
+		otherSnr := &snrList.Items[i]
+		timeBetween := otherSnr.CreationTimestamp.Sub(snr.CreationTimestamp.Time)
+		if timeBetween < -detection.TimeWindow.Duration || timeBetween > detection.TimeWindow.Duration {
+			continue
+		}
+		otherNode, err := r.lookupNodeOfSnr(otherSnr)
+		if err != nil {
+			// a remediation without a node shouldn't hold back the remediations of other nodes
+			r.logger.Info("ignoring SNR whose node can't be found for zone failure detection", "SNR name", otherSnr.Name, "error", err.Error())
+			continue
+		}
+		if zoneNodeNames[otherNode.Name] {
+			unhealthyNodeNames[otherNode.Name] = true
+		}
+	}
+
+	percentage := len(unhealthyNodeNames) * 100 / len(zoneNodes.Items)
+	msg := fmt.Sprintf("%d of %d nodes in zone %s need remediation within %s", len(unhealthyNodeNames), len(zoneNodes.Items), zone, detection.TimeWindow.Duration)
+	return len(unhealthyNodeNames) >= minZoneFailureUnhealthyNodes && percentage >= detection.UnhealthyNodesPercentage, msg, nil
+}
+
+// admitFencing returns true if the node of the given snr can be fenced without exceeding the max concurrent fencing limit.
+// Otherwise, it moves the snr to the Waiting phase and returns false
+func (r *SelfNodeRemediationReconciler) admitFencing(snr *v1alpha1.SelfNodeRemediation) (bool, error) {
+	maxFencedNodes, err := r.getMaxConcurrentFencing()
+	if err != nil {
+		return false, err
+	}
+
+	var fencedNodes int
+	if maxFencedNodes >= 0 {
+		if fencedNodes, err = r.countOtherFencedNodes(snr); err != nil {
+			return false, err
+		}
+	}
+
+	if maxFencedNodes < 0 || fencedNodes < maxFencedNodes {
+		r.markAdmitted(snr)
+		if r.getPhase(snr) == waitingPhase {
+			r.logger.Info("max concurrent fencing limit allows fencing the node", "fenced nodes", fencedNodes, "max concurrent fencing", maxFencedNodes)
+			r.setPhase(snr, fencingStartedPhase, phaseReasonFencingAdmitted)
+			meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+				Type:   v1alpha1.WaitingConditionType,
+				Status: metav1.ConditionFalse,
+				Reason: string(phaseReasonFencingAdmitted),
+			})
+		}
+		return true, nil
+	}
+
+	msg := fmt.Sprintf("%d nodes are already being fenced, which is the max concurrent fencing limit", fencedNodes)
+	if r.getPhase(snr) != waitingPhase {
+		r.logger.Info("max concurrent fencing limit reached, waiting for other remediations to finish", "fenced nodes", fencedNodes, "max concurrent fencing", maxFencedNodes)
+		r.setPhase(snr, waitingPhase, phaseReasonMaxConcurrentFencingReached)
+		r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationWaiting, "Remediation process - waiting for other remediations to finish: "+msg)
+	}
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.WaitingConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  string(phaseReasonMaxConcurrentFencingReached),
+		Message: msg,
+	})
+	return false, nil
+}
+
+// getMaxConcurrentFencing returns the max number of nodes which may be fenced at the same time, or -1 if there is no limit
+func (r *SelfNodeRemediationReconciler) getMaxConcurrentFencing() (int, error) {
+	config, err := r.getConfig()
+	if err != nil {
+		return 0, err
+	}
+	if config == nil || config.Spec.MaxConcurrentFencing == nil {
+		return -1, nil
+	}
+	maxConcurrentFencing := config.Spec.MaxConcurrentFencing
+
+	nodes := &v1.NodeList{}
+	if err = r.List(context.Background(), nodes); err != nil {
+		r.logger.Error(err, "failed to list nodes")
+		return 0, err
+	}
+
+	// round up, so that a non-zero percentage limit always allows fencing at least one node in small clusters
+	return intstr.GetScaledValueFromIntOrPercent(maxConcurrentFencing, len(nodes.Items), true)
+}
+
+// getConfig returns the SelfNodeRemediationConfig, or nil if it doesn't exist
+func (r *SelfNodeRemediationReconciler) getConfig() (*v1alpha1.SelfNodeRemediationConfig, error) {
+	ns, err := utils.GetDeploymentNamespace()
+	if err != nil {
+		r.logger.Error(err, "failed to get the deployment namespace")
+		return nil, err
+	}
+
+	config := &v1alpha1.SelfNodeRemediationConfig{}
+	if err = r.Get(context.Background(), client.ObjectKey{Name: v1alpha1.ConfigCRName, Namespace: ns}, config); err != nil {
+		if apiErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		r.logger.Error(err, "failed to get SelfNodeRemediationConfig")
+		return nil, err
+	}
+	return config, nil
+}
+
+// countOtherFencedNodes returns the number of nodes, other than the node of the given snr, which are currently fenced
+// by self node remediation. Snrs which were admitted, but don't have the finalizer in the cache yet, are counted too
+func (r *SelfNodeRemediationReconciler) countOtherFencedNodes(snr *v1alpha1.SelfNodeRemediation) (int, error) {
+	snrList := &v1alpha1.SelfNodeRemediationList{}
+	if err := r.List(context.Background(), snrList); err != nil {
+		r.logger.Error(err, "failed to list SNRs")
+		return 0, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	existingSnrs := make(map[types.UID]bool, len(snrList.Items))
+	fencedNodes := 0
+	for i := range snrList.Items {
+		otherSnr := &snrList.Items[i]
+		existingSnrs[otherSnr.UID] = true
+		if otherSnr.UID == snr.UID {
+			continue
+		}
+		if controllerutil.ContainsFinalizer(otherSnr, SNRFinalizer) || r.admittedSnrs[otherSnr.UID] {
+			fencedNodes++
+		}
+	}
+	// deleted snrs don't hold back other remediations anymore
+	for uid := range r.admittedSnrs {
+		if !existingSnrs[uid] {
+			delete(r.admittedSnrs, uid)
+		}
+	}
+	return fencedNodes, nil
+}
+
+// markAdmitted records that fencing the node of the given snr was admitted
+func (r *SelfNodeRemediationReconciler) markAdmitted(snr *v1alpha1.SelfNodeRemediation) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.admittedSnrs == nil {
+		r.admittedSnrs = map[types.UID]bool{}
+	}
+	r.admittedSnrs[snr.UID] = true
+}
+
+func (r *SelfNodeRemediationReconciler) prepareReboot(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	r.logger.Info("pre-reboot not completed yet, prepare for rebooting")
+	if !r.isNodeRebootCapable(node) {
+		//use err to trigger exponential backoff
+		return ctrl.Result{}, errors.New("Node is not capable to reboot itself")
+	}
+
+	if snr.Spec.DryRun {
+		return r.simulatePrepareReboot(node, snr)
+	}
+
+	if !controllerutil.ContainsFinalizer(snr, SNRFinalizer) {
+		return r.addFinalizer(snr)
+	}
+
+	if len(snr.Status.Timeline) == 0 {
+		r.addTimelineEntry(snr, fencingStartedPhase, phaseReasonRemediationStarted)
+	}
+
+	if snr.Status.BootIDBeforeFencing == "" {
+		snr.Status.BootIDBeforeFencing = node.Status.NodeInfo.BootID
+	}
+
+	if snr.Status.PreRemediationNodeState == nil {
+		r.recordPreRemediationNodeState(node, snr)
+		// the state must be saved before the node is changed, otherwise the changes would be recorded as the original state
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if r.getRemediationStrategy(snr) == v1alpha1.NodeDeletionRemediationStrategy && snr.Status.NodeBackup == nil {
+		r.backupNode(node, snr)
+	}
+
+	// same as kubectl drain, the node is cordoned before its pods are evicted, so that they aren't scheduled on it again
+	if !node.Spec.Unschedulable || !utils.TaintExists(node.Spec.Taints, NodeUnschedulableTaint) {
+		return r.markNodeAsUnschedulable(node)
+	}
+
+	if snr.Spec.DrainTimeout != nil {
+		isDrainDone, err := r.drainNode(node, snr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !isDrainDone {
+			return ctrl.Result{RequeueAfter: drainRequeueInterval}, nil
+		}
+	}
+
+	if err := r.addNoExecuteTaint(node); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if snr.Status.TimeAssumedRebooted.IsZero() {
+		r.updateTimeAssumedRebooted(node, snr)
+	}
+
+	r.setPhase(snr, preRebootCompletedPhase, phaseReasonNodeFenced)
+
+	return ctrl.Result{}, nil
+}
+
+// recordPreRemediationNodeState records whether the node is cordoned, and which of the taints the remediation
+// adds or removes it already has, so that recoverNode restores the node to that state
+func (r *SelfNodeRemediationReconciler) recordPreRemediationNodeState(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) {
+	state := &v1alpha1.NodeState{
+		Unschedulable: node.Spec.Unschedulable,
+	}
+	for _, remediationTaint := range []*v1.Taint{NodeNoExecuteTaint, OutOfServiceTaint} {
+		for _, taint := range node.Spec.Taints {
+			if taint.MatchTaint(remediationTaint) {
+				state.Taints = append(state.Taints, taint)
+			}
+		}
+	}
+	r.logger.Info("recording the node state before fencing it", "node name", node.Name, "unschedulable", state.Unschedulable, "taints", state.Taints)
+	snr.Status.PreRemediationNodeState = state
+}
+
+// hadTaintBeforeRemediation returns true if the node already had the given taint before it was fenced
+func (r *SelfNodeRemediationReconciler) hadTaintBeforeRemediation(snr *v1alpha1.SelfNodeRemediation, taint *v1.Taint) bool {
+	return snr.Status.PreRemediationNodeState != nil && utils.TaintExists(snr.Status.PreRemediationNodeState.Taints, taint)
+}
+
+// simulatePrepareReboot records the changes prepareReboot would make to the node, without making them.
+// No finalizer is needed, since there is nothing to clean up
+func (r *SelfNodeRemediationReconciler) simulatePrepareReboot(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	if len(snr.Status.Timeline) == 0 {
+		r.addTimelineEntry(snr, fencingStartedPhase, phaseReasonRemediationStarted)
+	}
+
+	r.recordDryRunEvent(node, "unhealthy node would be marked as unschedulable")
+	if snr.Spec.DrainTimeout != nil {
+		r.recordDryRunEvent(node, "pods of the unhealthy node would be evicted")
+	}
+	r.recordDryRunEvent(node, "NoExecute taint would be added to the unhealthy node")
+
+	if snr.Status.TimeAssumedRebooted.IsZero() {
+		r.updateTimeAssumedRebooted(node, snr)
+	}
+
+	r.setPhase(snr, preRebootCompletedPhase, phaseReasonNodeFenced)
+
+	return ctrl.Result{}, nil
+}
+
+// drainNode evicts the pods of the node before fencing it, and returns true once all pods were evicted or the drain timed out.
+// Same as kubectl drain, DaemonSet pods (which include the self node remediation agent) and mirror pods aren't evicted
+func (r *SelfNodeRemediationReconciler) drainNode(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (bool, error) {
+	if snr.Status.Drain == nil {
+		r.logger.Info("draining node before fencing it", "node name", node.Name)
+		snr.Status.Drain = &v1alpha1.DrainStatus{
+			State:     v1alpha1.DrainInProgress,
+			StartTime: metav1.Now(),
+		}
+	}
+	drain := snr.Status.Drain
+	if drain.State != v1alpha1.DrainInProgress {
+		return true, nil
+	}
+
+	if time.Since(drain.StartTime.Time) > snr.Spec.DrainTimeout.Duration {
+		drain.State = v1alpha1.DrainTimedOut
+		r.logger.Info("drain timed out, fencing the node", "node name", node.Name, "pending pods", drain.PendingPods)
+		r.Recorder.Event(node, eventTypeNormal, eventReasonDrainTimedOut, fmt.Sprintf("Remediation process - drain timed out with %d pods which weren't evicted", drain.PendingPods))
+		return true, nil
+	}
+
+	pods := &v1.PodList{}
+	if err := r.Client.List(context.Background(), pods, client.MatchingFields{podNodeNameField: node.Name}); err != nil {
+		r.logger.Error(err, "failed to get pod list")
+		return false, err
+	}
+
+	pendingPods := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isEvictablePod(pod) {
+			continue
+		}
+		pendingPods++
+		if r.isPodTerminating(pod) {
+			continue
+		}
+		eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+		err := r.Client.SubResource("eviction").Create(context.Background(), pod, eviction)
+		switch {
+		case err == nil:
+		case apiErrors.IsNotFound(err):
+			// the pod is already gone
+			pendingPods--
+		case apiErrors.IsTooManyRequests(err):
+			// the eviction would violate a PodDisruptionBudget, it's retried on the next check
+		default:
+			r.logger.Error(err, "failed to evict pod", "pod name", pod.Name, "namespace", pod.Namespace)
+		}
+	}
+	drain.PendingPods = pendingPods
+
+	if pendingPods > 0 {
+		return false, nil
+	}
+	drain.State = v1alpha1.DrainCompleted
+	r.logger.Info("drain completed", "node name", node.Name)
+	r.Recorder.Event(node, eventTypeNormal, eventReasonDrainCompleted, "Remediation process - all pods of the unhealthy node were evicted")
+	return true, nil
+}
+
+// isEvictablePod returns false for pods which are not evicted when draining a node
+func isEvictablePod(pod *v1.Pod) bool {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return false
+	}
+	if _, isMirrorPod := pod.Annotations[v1.MirrorPodAnnotationKey]; isMirrorPod {
+		return false
+	}
+	for _, ownerRef := range pod.OwnerReferences {
+		if ownerRef.Kind == "DaemonSet" {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *SelfNodeRemediationReconciler) handlePreRebootCompletedPhase(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	if snr.Spec.RebootApproval != nil {
+		isApproved, result, err := r.awaitRebootApproval(node, snr)
+		if err != nil || !isApproved {
+			return result, err
+		}
+	}
+	return r.rebootNode(node, snr)
+}
+
+// awaitRebootApproval returns true once rebooting the node was approved, either by the approval annotation of the snr
+// or by the approval timeout. Both the agent and the manager wait for the approval, so that the node isn't rebooted
+// and its workloads aren't released before that
+func (r *SelfNodeRemediationReconciler) awaitRebootApproval(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (bool, ctrl.Result, error) {
+	approvedCond := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.RebootApprovedConditionType)
+	if approvedCond != nil && approvedCond.Status == metav1.ConditionTrue {
+		return true, ctrl.Result{}, nil
+	}
+
+	if approvedCond != nil && approvedCond.Status == metav1.ConditionFalse {
+		// the remediation was aborted, the node stays fenced until the snr is deleted
+		metrics.SetRemediationInProgress(node.Name, false)
+		if snr.DeletionTimestamp != nil && !snr.Spec.DryRun {
+			result, err := r.recoverNode(node, snr)
+			return false, result, err
+		}
+		return false, ctrl.Result{}, nil
+	}
+
+	if snr.Annotations[v1alpha1.RebootApprovedAnnotation] == "true" {
+		r.approveReboot(node, snr, rebootApprovedReason, "reboot was approved by the reboot-approved annotation")
+		return true, ctrl.Result{}, nil
+	}
+
+	if approvedCond == nil {
+		meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.RebootApprovedConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  approvalPendingReason,
+			Message: fmt.Sprintf("waiting for the %s annotation to be set to \"true\"", v1alpha1.RebootApprovedAnnotation),
+		})
+		approvedCond = meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.RebootApprovedConditionType)
+		r.Recorder.Event(snr, eventTypeNormal, eventReasonAwaitingApproval, "Remediation process - waiting for the approval of rebooting the unhealthy node")
+	}
+
+	timeout := snr.Spec.RebootApproval.Timeout
+	if timeout == nil {
+		// changing the annotation triggers a reconcile, no need to requeue
+		return false, ctrl.Result{}, nil
+	}
+
+	if timeLeft := time.Until(approvedCond.LastTransitionTime.Add(timeout.Duration)); timeLeft > 0 {
+		return false, ctrl.Result{RequeueAfter: timeLeft + time.Second}, nil
+	}
+
+	if snr.Spec.RebootApproval.TimeoutAction == v1alpha1.ApproveRebootApprovalTimeoutAction {
+		r.approveReboot(node, snr, approvalTimedOutReason, fmt.Sprintf("reboot was approved automatically, since it wasn't approved within %s", timeout.Duration))
+		return true, ctrl.Result{}, nil
+	}
+
+	msg := fmt.Sprintf("reboot wasn't approved within %s, leaving the node fenced", timeout.Duration)
+	r.logger.Info("reboot approval timed out, aborting the remediation", "node name", node.Name, "timeout", timeout.Duration)
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.RebootApprovedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  approvalTimedOutReason,
+		Message: msg,
+	})
+	r.Recorder.Event(snr, eventTypeWarning, eventReasonRemediationAborted, "Remediation process - remediation aborted: "+msg)
+	metrics.SetRemediationInProgress(node.Name, false)
+	return false, ctrl.Result{}, r.updateConditions(remediationAborted, snr)
+}
+
+// approveReboot marks the reboot as approved. The time the node is assumed to be rebooted is updated along with it,
+// since the node only starts rebooting after the approval
+func (r *SelfNodeRemediationReconciler) approveReboot(node *v1.Node, snr *v1alpha1.SelfNodeRemediation, reason, msg string) {
+	r.logger.Info("reboot approved", "node name", node.Name, "reason", reason)
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.RebootApprovedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: msg,
+	})
+	r.updateTimeAssumedRebooted(node, snr)
+	r.Recorder.Event(snr, eventTypeNormal, eventReasonRebootApproved, "Remediation process - "+msg)
+}
+
+func (r *SelfNodeRemediationReconciler) rebootNode(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	r.logger.Info("node reboot not completed yet, start rebooting")
+	if r.MyNodeName == node.Name {
+		if snr.Spec.DryRun {
+			r.recordDryRunEvent(node, "unhealthy node would be rebooted")
+			return ctrl.Result{}, nil
+		}
+		// we have a problem on this node, reboot!
+		return r.rebootIfNeeded(snr, node)
+	}
+
+	wasRebooted, reason, timeLeft := r.wasNodeRebooted(node, snr)
 	if !wasRebooted {
 		return ctrl.Result{RequeueAfter: timeLeft}, nil
 	}
 
-	r.logger.Info("TimeAssumedRebooted is old. The unhealthy node assumed to been rebooted", "node name", node.Name)
+	if reason == phaseReasonNodeBootIDChanged {
+		r.logger.Info("The unhealthy node reports a new boot ID and is Ready, so it was rebooted", "node name", node.Name,
+			"previous boot ID", snr.Status.BootIDBeforeFencing, "boot ID", node.Status.NodeInfo.BootID)
+	} else {
+		r.logger.Info("TimeAssumedRebooted is old. The unhealthy node assumed to been rebooted", "node name", node.Name)
+	}
 
-	rebootCompleted := string(rebootCompletedPhase)
-	snr.Status.Phase = &rebootCompleted
+	r.setPhase(snr, rebootCompletedPhase, reason)
+	if !snr.Spec.DryRun {
+		r.observeReboot(reason)
+	}
 
 	return ctrl.Result{}, nil
 }
 
+// observeReboot counts the reboot of a remediated node, by the way its reboot was detected. The agent can't count it
+// itself, since the reboot ends it before its metrics are scraped
+func (r *SelfNodeRemediationReconciler) observeReboot(reason phaseChangeReason) {
+	if r.IsAgent() {
+		return
+	}
+	if reason == phaseReasonNodeBootIDChanged {
+		metrics.ObserveReboot(metrics.RebootMethodBootIDChanged)
+		return
+	}
+	metrics.ObserveReboot(metrics.RebootMethodAssumed)
+}
+
 func (r *SelfNodeRemediationReconciler) handleRebootCompletedPhase(node *v1.Node, snr *v1alpha1.SelfNodeRemediation, rmNodeResources removeNodeResources) (ctrl.Result, error) {
-	// if err is non-nil, exponential backoff is triggered
-	// if err is nil and waitTime is not a 'zero' time, wait for waitTime seconds to remove node resources
-	if waitTime, err := rmNodeResources(node, snr); err != nil {
-		return ctrl.Result{}, err
-	} else if waitTime != 0 {
-		return ctrl.Result{RequeueAfter: waitTime}, nil
+	// the RebootOnly remediation strategy keeps the workloads of the node in place, so a remediation which is
+	// deleted while waiting for the node to be Ready again has nothing left to wait for
+	if snr.DeletionTimestamp != nil && !snr.Spec.DryRun && r.getRemediationStrategy(snr) == v1alpha1.RebootOnlyRemediationStrategy {
+		return r.recoverNode(node, snr)
 	}
-	r.Recorder.Event(node, eventTypeNormal, eventReasonDeleteResources, "Remediation process - finished deleting unhealthy node resources")
 
-	fencingCompleted := string(fencingCompletedPhase)
-	snr.Status.Phase = &fencingCompleted
+	if snr.Spec.DryRun {
+		if err := r.simulateNodeResourcesRemoval(node, snr); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		// if err is non-nil, exponential backoff is triggered
+		// if err is nil and waitTime is not a 'zero' time, wait for waitTime seconds to remove node resources
+		if waitTime, err := rmNodeResources(node, snr); err != nil {
+			return ctrl.Result{}, err
+		} else if waitTime != 0 {
+			return ctrl.Result{RequeueAfter: waitTime}, nil
+		}
+		if r.getRemediationStrategy(snr) != v1alpha1.RebootOnlyRemediationStrategy {
+			r.Recorder.Event(node, eventTypeNormal, eventReasonDeleteResources, "Remediation process - finished deleting unhealthy node resources")
+		}
+	}
+
+	if r.getRemediationStrategy(snr) == v1alpha1.RebootOnlyRemediationStrategy {
+		r.setPhase(snr, fencingCompletedPhase, phaseReasonNodeReadyAfterReboot)
+	} else {
+		r.setPhase(snr, fencingCompletedPhase, phaseReasonResourcesRemoved)
+	}
 
 	return ctrl.Result{}, r.updateConditions(remediationFinishedSuccessfully, snr)
 }
@@ -461,32 +1845,281 @@ func (r *SelfNodeRemediationReconciler) handleFencingCompletedPhase(node *v1.Nod
 	var err error
 
 	if snr.DeletionTimestamp != nil {
-		result, err = r.recoverNode(node, snr)
+		// a dry run didn't change the node, so there is nothing to recover
+		if !snr.Spec.DryRun {
+			result, err = r.recoverNode(node, snr)
+		}
+	} else {
+		// a dry run didn't reboot the node, so there is no recovery to verify
+		if snr.Spec.RecoveryVerificationTimeout != nil && !snr.Spec.DryRun {
+			if result, err = r.verifyNodeRecovery(node, snr); err != nil {
+				return result, err
+			}
+		}
+		if snr.Spec.Escalation != nil {
+			escalationResult, escalationErr := r.escalateIfNodeNotReady(node, snr)
+			if escalationErr != nil {
+				return escalationResult, escalationErr
+			}
+			// requeue for whichever of the two needs it first
+			if result.RequeueAfter == 0 || (escalationResult.RequeueAfter > 0 && escalationResult.RequeueAfter < result.RequeueAfter) {
+				result = escalationResult
+			}
+		}
 	}
 
 	return result, err
 }
 
+// isNodeReadyAfterReboot returns whether the node is Ready, and whether it was rebooted according to its boot ID
+func (r *SelfNodeRemediationReconciler) isNodeReadyAfterReboot(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (bool, bool) {
+	readyCond := r.getReadyCond(node)
+	isReady := readyCond != nil && readyCond.Status == v1.ConditionTrue
+	bootID := node.Status.NodeInfo.BootID
+	isRebooted := bootID != "" && bootID != snr.Status.BootIDBeforeFencing
+	return isReady, isRebooted
+}
+
+// verifyNodeRecovery reports in the NodeRecovered condition whether the node came back healthy after fencing was completed,
+// which is when it's Ready with a boot ID that differs from its boot ID before fencing
+func (r *SelfNodeRemediationReconciler) verifyNodeRecovery(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	if recoveredCond := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.NodeRecoveredConditionType); recoveredCond != nil && recoveredCond.Status != metav1.ConditionUnknown {
+		// verification is done
+		return ctrl.Result{}, nil
+	}
+
+	if r.getPhase(snr) != verifyingRecoveryPhase {
+		r.setPhase(snr, verifyingRecoveryPhase, phaseReasonRecoveryVerificationStarted)
+		meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.NodeRecoveredConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "VerifyingRecovery",
+			Message: "waiting for the node to be Ready after it was rebooted",
+		})
+	}
+
+	isReady, isRebooted := r.isNodeReadyAfterReboot(node, snr)
+	if isReady && isRebooted {
+		r.logger.Info("node recovered after fencing was completed", "node name", node.Name, "boot ID", node.Status.NodeInfo.BootID)
+		meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+			Type:    v1alpha1.NodeRecoveredConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "NodeReadyAfterReboot",
+			Message: "node is Ready with a new boot ID",
+		})
+		r.Recorder.Event(snr, eventTypeNormal, eventReasonNodeRecovered, "Remediation process - node is Ready after it was rebooted")
+		return ctrl.Result{}, nil
+	}
+
+	deadline := r.getFencingCompletedTime(snr).Add(snr.Spec.RecoveryVerificationTimeout.Duration)
+	if timeLeft := time.Until(deadline); timeLeft > 0 {
+		if timeLeft > recoveryVerificationRequeueInterval {
+			timeLeft = recoveryVerificationRequeueInterval
+		}
+		return ctrl.Result{RequeueAfter: timeLeft}, nil
+	}
+
+	msg := fmt.Sprintf("node didn't recover within %s after fencing was completed, Ready: %t, rebooted: %t", snr.Spec.RecoveryVerificationTimeout.Duration, isReady, isRebooted)
+	r.logger.Info("node didn't recover after fencing was completed", "node name", node.Name, "Ready", isReady, "rebooted", isRebooted)
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.NodeRecoveredConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "RecoveryVerificationTimedOut",
+		Message: msg,
+	})
+	r.Recorder.Event(snr, eventTypeWarning, eventReasonNodeNotRecovered, "Remediation process - "+msg)
+	return ctrl.Result{}, nil
+}
+
+// escalateIfNodeNotReady creates the secondary remediation defined by the snr escalation,
+// if the node isn't Ready when the escalation timeout after completing fencing has passed
+func (r *SelfNodeRemediationReconciler) escalateIfNodeNotReady(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
+	// the escalation is done, or it failed because of missing permissions, which retrying won't fix
+	if meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.EscalatedConditionType) != nil {
+		return ctrl.Result{}, nil
+	}
+
+	escalationTime := r.getFencingCompletedTime(snr).Add(snr.Spec.Escalation.Timeout.Duration)
+	if timeLeft := time.Until(escalationTime); timeLeft > 0 {
+		return ctrl.Result{RequeueAfter: timeLeft}, nil
+	}
+
+	if readyCond := r.getReadyCond(node); readyCond != nil && readyCond.Status == v1.ConditionTrue {
+		r.logger.Info("node is Ready after fencing was completed, no need to escalate", "node name", node.Name)
+		return ctrl.Result{}, nil
+	}
+
+	templateRef := snr.Spec.Escalation.RemediationTemplate
+	if snr.Spec.DryRun {
+		r.recordDryRunEvent(snr, fmt.Sprintf("node isn't Ready, remediation would be escalated using %s %s", templateRef.Kind, templateRef.Name))
+		return ctrl.Result{}, nil
+	}
+
+	remediation, err := r.createEscalationRemediation(snr)
+	if err != nil {
+		if apiErrors.IsForbidden(err) {
+			msg := fmt.Sprintf("not permitted to escalate using %s %s, the ClusterRole of the secondary remediator needs the %s=true label: %v",
+				templateRef.Kind, templateRef.Name, escalationAggregationLabel, err)
+			r.logger.Error(err, "failed to escalate remediation, missing permissions", "kind", templateRef.Kind, "name", templateRef.Name)
+			meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+				Type:    v1alpha1.EscalatedConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "EscalationForbidden",
+				Message: msg,
+			})
+			r.Recorder.Event(snr, eventTypeWarning, eventReasonRemediationEscalationFailed, "Remediation process - "+msg)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	msg := fmt.Sprintf("node isn't Ready %s after fencing was completed, created %s %s", snr.Spec.Escalation.Timeout.Duration, remediation.GetKind(), remediation.GetName())
+	r.logger.Info("remediation escalated", "kind", remediation.GetKind(), "name", remediation.GetName())
+	meta.SetStatusCondition(&snr.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.EscalatedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "NodeNotReadyAfterFencing",
+		Message: msg,
+	})
+	r.Recorder.Event(snr, eventTypeNormal, eventReasonRemediationEscalated, "Remediation process - "+msg)
+	return ctrl.Result{}, nil
+}
+
+// getFencingCompletedTime returns the time the snr moved to the Fencing-Completed phase.
+// It falls back to the time the node was assumed to be rebooted, for snrs without a timeline entry for that phase,
+// e.g. snrs which were created by an older version
+func (r *SelfNodeRemediationReconciler) getFencingCompletedTime(snr *v1alpha1.SelfNodeRemediation) time.Time {
+	for i := len(snr.Status.Timeline) - 1; i >= 0; i-- {
+		if snr.Status.Timeline[i].Phase == string(fencingCompletedPhase) {
+			return snr.Status.Timeline[i].TransitionTime.Time
+		}
+	}
+	if snr.Status.TimeAssumedRebooted != nil {
+		return snr.Status.TimeAssumedRebooted.Time
+	}
+	return snr.CreationTimestamp.Time
+}
+
+// createEscalationRemediation creates the remediation of the secondary remediator, based on the spec of the escalation template.
+// The remediation is owned by the snr, so it is deleted together with the snr when the node is healthy again
+func (r *SelfNodeRemediationReconciler) createEscalationRemediation(snr *v1alpha1.SelfNodeRemediation) (*unstructured.Unstructured, error) {
+	templateRef := snr.Spec.Escalation.RemediationTemplate
+	template := &unstructured.Unstructured{}
+	template.SetAPIVersion(templateRef.APIVersion)
+	template.SetKind(templateRef.Kind)
+	if err := r.Get(context.Background(), client.ObjectKey{Name: templateRef.Name, Namespace: snr.Namespace}, template); err != nil {
+		r.logger.Error(err, "failed to get escalation remediation template", "kind", templateRef.Kind, "name", templateRef.Name)
+		return nil, err
+	}
+
+	remediation := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	spec, found, err := unstructured.NestedMap(template.Object, "spec", "template", "spec")
+	if err != nil {
+		r.logger.Error(err, "failed to get spec from escalation remediation template", "kind", templateRef.Kind, "name", templateRef.Name)
+		return nil, err
+	}
+	if found {
+		remediation.Object["spec"] = spec
+	}
+	remediation.SetAPIVersion(templateRef.APIVersion)
+	remediation.SetKind(strings.TrimSuffix(templateRef.Kind, "Template"))
+	remediation.SetName(snr.Name)
+	remediation.SetNamespace(snr.Namespace)
+	remediation.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: v1alpha1.GroupVersion.String(),
+		Kind:       "SelfNodeRemediation",
+		Name:       snr.Name,
+		UID:        snr.UID,
+	}})
+
+	if err = r.Create(context.Background(), remediation); err != nil {
+		if apiErrors.IsAlreadyExists(err) {
+			// created by a previous reconcile, which failed to update the snr status
+			return remediation, nil
+		}
+		r.logger.Error(err, "failed to create escalation remediation", "kind", remediation.GetKind(), "name", remediation.GetName())
+		return nil, err
+	}
+	return remediation, nil
+}
+
+// simulateNodeResourcesRemoval records the node resources which would be removed by the remediation strategy, without removing them
+func (r *SelfNodeRemediationReconciler) simulateNodeResourcesRemoval(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) error {
+	strategy := r.getRemediationStrategy(snr)
+	switch strategy {
+	case v1alpha1.OutOfServiceTaintRemediationStrategy:
+		r.recordDryRunEvent(node, "out-of-service taint would be added to the unhealthy node")
+	case v1alpha1.NodeDeletionRemediationStrategy:
+		r.recordDryRunEvent(node, "unhealthy node would be deleted")
+	case v1alpha1.RebootOnlyRemediationStrategy:
+		r.recordDryRunEvent(node, "workloads of the unhealthy node would be kept in place")
+		return nil
+	}
+
+	pods := &v1.PodList{}
+	if err := r.Client.List(context.Background(), pods, client.MatchingFields{podNodeNameField: node.Name}); err != nil {
+		r.logger.Error(err, "failed to get pod list")
+		return err
+	}
+	for _, pod := range pods.Items {
+		if strategy == v1alpha1.ResourceDeletionRemediationStrategy && pod.Annotations[v1alpha1.ExcludeFromDeletionAnnotation] == "true" {
+			r.recordDryRunEvent(node, fmt.Sprintf("pod %s/%s is excluded from deletion", pod.Namespace, pod.Name))
+		} else {
+			r.recordDryRunEvent(node, fmt.Sprintf("pod %s/%s would be deleted", pod.Namespace, pod.Name))
+		}
+	}
+
+	volumeAttachments := &storagev1.VolumeAttachmentList{}
+	if err := r.Client.List(context.Background(), volumeAttachments); err != nil {
+		r.logger.Error(err, "failed to get volumeAttachments list")
+		return err
+	}
+	for _, va := range volumeAttachments.Items {
+		if va.Spec.NodeName == node.Name {
+			r.recordDryRunEvent(node, fmt.Sprintf("volumeAttachment %s would be deleted", va.Name))
+		}
+	}
+
+	return nil
+}
+
+// recordDryRunEvent records an event for an action which was skipped because the remediation is a dry run
+func (r *SelfNodeRemediationReconciler) recordDryRunEvent(obj runtime.Object, msg string) {
+	r.Recorder.Event(obj, eventTypeNormal, eventReasonDryRun, "Remediation process (dry run) - "+msg)
+}
+
 func (r *SelfNodeRemediationReconciler) recoverNode(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (ctrl.Result, error) {
 	r.logger.Info("fencing completed, cleaning up")
-	if node.Spec.Unschedulable {
-		node.Spec.Unschedulable = false
+	// restore the state the node had before it was fenced, e.g. keep it cordoned if an admin cordoned it
+	wasUnschedulable := snr.Status.PreRemediationNodeState != nil && snr.Status.PreRemediationNodeState.Unschedulable
+	if node.Spec.Unschedulable != wasUnschedulable {
+		node.Spec.Unschedulable = wasUnschedulable
 		if err := r.Client.Update(context.Background(), node); err != nil {
 			if apiErrors.IsConflict(err) {
 				return ctrl.Result{RequeueAfter: time.Second}, nil
 			}
-			r.logger.Error(err, "failed to unmark node as schedulable")
+			r.logger.Error(err, "failed to restore the unschedulable state of the node", "unschedulable", wasUnschedulable)
 			return ctrl.Result{}, err
 		}
-		r.Recorder.Event(node, eventTypeNormal, eventReasonMarkSchedulable, "Remediation process - mark healthy remediated node as schedulable")
+		if wasUnschedulable {
+			r.logger.Info("node was cordoned before it was fenced, cordoned it again", "node name", node.Name)
+		} else {
+			r.Recorder.Event(node, eventTypeNormal, eventReasonMarkSchedulable, "Remediation process - mark healthy remediated node as schedulable")
+		}
 	}
 
 	// wait until NoSchedulable taint was removed
-	if utils.TaintExists(node.Spec.Taints, NodeUnschedulableTaint) {
+	if !wasUnschedulable && utils.TaintExists(node.Spec.Taints, NodeUnschedulableTaint) {
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
 
-	if err := r.removeNoExecuteTaint(node); err != nil {
+	if !r.hadTaintBeforeRemediation(snr, NodeNoExecuteTaint) {
+		if err := r.removeNoExecuteTaint(node); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.restorePreRemediationTaints(node, snr); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -497,11 +2130,46 @@ func (r *SelfNodeRemediationReconciler) recoverNode(node *v1.Node, snr *v1alpha1
 		r.Recorder.Event(snr, eventTypeNormal, eventReasonRemoveFinalizer, "Remediation process - remove finalizer from snr")
 	}
 
+	r.observePhaseDuration(snr)
+	metrics.SetRemediationInProgress(node.Name, false)
+
 	return ctrl.Result{}, nil
 }
 
+// restorePreRemediationTaints re-adds the taints the node had before it was fenced, in case they got lost,
+// e.g. when the node was re-created by the NodeDeletion remediation strategy
+func (r *SelfNodeRemediationReconciler) restorePreRemediationTaints(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) error {
+	if snr.Status.PreRemediationNodeState == nil {
+		return nil
+	}
+
+	patch := client.MergeFrom(node.DeepCopy())
+	isRestored := false
+	for _, taint := range snr.Status.PreRemediationNodeState.Taints {
+		if !utils.TaintExists(node.Spec.Taints, &taint) {
+			node.Spec.Taints = append(node.Spec.Taints, taint)
+			isRestored = true
+		}
+	}
+	if !isRestored {
+		return nil
+	}
+
+	if err := r.Client.Patch(context.Background(), node, patch); err != nil {
+		r.logger.Error(err, "failed to restore the taints of the node", "node name", node.Name)
+		return err
+	}
+	r.logger.Info("taints the node had before it was fenced restored", "new taints", node.Spec.Taints)
+	return nil
+}
+
 // rebootIfNeeded reboots the node if no reboot was performed so far
 func (r *SelfNodeRemediationReconciler) rebootIfNeeded(snr *v1alpha1.SelfNodeRemediation, node *v1.Node) (ctrl.Result, error) {
+	if snr.Spec.RebootApproval != nil && !meta.IsStatusConditionTrue(snr.Status.Conditions, v1alpha1.RebootApprovedConditionType) {
+		r.logger.Info("reboot wasn't approved yet, skipping reboot")
+		return ctrl.Result{}, nil
+	}
+
 	shouldAvoidReboot, err := r.didIRebootMyself(snr)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -515,16 +2183,29 @@ func (r *SelfNodeRemediationReconciler) rebootIfNeeded(snr *v1alpha1.SelfNodeRem
 	return ctrl.Result{RequeueAfter: reboot.TimeToAssumeRebootHasStarted}, r.Rebooter.Reboot()
 }
 
-// wasNodeRebooted returns true if the node assumed to been rebooted.
-// if not, it will also return the remaining time for that to happen
-func (r *SelfNodeRemediationReconciler) wasNodeRebooted(snr *v1alpha1.SelfNodeRemediation) (bool, time.Duration) {
+// wasNodeRebooted returns true if the node assumed to been rebooted, along with the reason for that.
+// A node which reports a new boot ID and is Ready was rebooted for sure, otherwise the node is assumed to been rebooted
+// once TimeAssumedRebooted passed. If not, it will also return the remaining time for that to happen
+func (r *SelfNodeRemediationReconciler) wasNodeRebooted(node *v1.Node, snr *v1alpha1.SelfNodeRemediation) (bool, phaseChangeReason, time.Duration) {
+	// without the boot ID from before fencing, a reboot can't be proven
+	canProveReboot := snr.Status.BootIDBeforeFencing != "" && !snr.Spec.DryRun
+	if canProveReboot {
+		if isReady, isRebooted := r.isNodeReadyAfterReboot(node, snr); isReady && isRebooted {
+			return true, phaseReasonNodeBootIDChanged, 0
+		}
+	}
+
 	maxNodeRebootTime := snr.Status.TimeAssumedRebooted
 
 	if maxNodeRebootTime.After(time.Now()) {
-		return false, maxNodeRebootTime.Sub(time.Now()) + time.Second
+		timeLeft := maxNodeRebootTime.Sub(time.Now()) + time.Second
+		if canProveReboot && timeLeft > bootIDCheckRequeueInterval {
+			timeLeft = bootIDCheckRequeueInterval
+		}
+		return false, "", timeLeft
 	}
 
-	return true, 0
+	return true, phaseReasonNodeRebooted, 0
 }
 
 // didIRebootMyself returns true if system uptime is less than the time from SNR creation timestamp
@@ -636,37 +2317,77 @@ func (r *SelfNodeRemediationReconciler) updateTimeAssumedRebooted(node *v1.Node,
 	r.Recorder.Event(snr, eventTypeNormal, eventReasonUpdateTimeAssumedRebooted, "Remediation process - about to update required fencing time on snr")
 }
 
-// getNodeFromSnr returns the unhealthy node reported in the given snr
+// getNodeFromSnr returns the unhealthy node reported in the given snr, and records whether it was reported by a machine
 func (r *SelfNodeRemediationReconciler) getNodeFromSnr(snr *v1alpha1.SelfNodeRemediation) (*v1.Node, error) {
-	//SNR could be created by either machine based controller (e.g. MHC) or
-	//by a node based controller (e.g. NHC). This assumes that machine based controller
-	//will create the snr with machine owner reference
+	if getMachineOwnerRef(snr) != nil {
+		r.mutex.Lock()
+		wasLastSeenSnrMachine = true
+		r.mutex.Unlock()
+	}
+	return r.lookupNodeOfSnr(snr)
+}
 
-	for _, ownerRef := range snr.OwnerReferences {
-		if ownerRef.Kind == "Machine" {
-			r.mutex.Lock()
-			wasLastSeenSnrMachine = true
-			r.mutex.Unlock()
-			return r.getNodeFromMachine(ownerRef, snr.Namespace)
+// getMachineOwnerRef returns the machine owner reference of the given snr, or nil if it isn't owned by a machine
+func getMachineOwnerRef(snr *v1alpha1.SelfNodeRemediation) *metav1.OwnerReference {
+	for i := range snr.OwnerReferences {
+		if snr.OwnerReferences[i].Kind == "Machine" {
+			return &snr.OwnerReferences[i]
 		}
 	}
+	return nil
+}
+
+// lookupNodeOfSnr returns the unhealthy node reported in the given snr
+func (r *SelfNodeRemediationReconciler) lookupNodeOfSnr(snr *v1alpha1.SelfNodeRemediation) (*v1.Node, error) {
+	nodeName, err := r.getNodeNameOfSnr(snr)
+	if err != nil {
+		return nil, err
+	}
 
-	//since we didn't find a machine owner ref, we assume that snr name is the unhealthy node name
 	node := &v1.Node{}
 	key := client.ObjectKey{
-		Name:      snr.Name,
+		Name:      nodeName,
 		Namespace: "",
 	}
 
 	if err := r.Get(context.TODO(), key, node); err != nil {
+		if getMachineOwnerRef(snr) != nil {
+			r.logger.Error(err, "failed to retrieve node from the unhealthy machine", "node name", nodeName)
+		}
 		return nil, err
 	}
 
 	return node, nil
 }
 
-func (r *SelfNodeRemediationReconciler) getNodeFromMachine(ref metav1.OwnerReference, ns string) (*v1.Node, error) {
-	machine := &v1beta1.Machine{}
+// getNodeNameOfSnr returns the name of the unhealthy node reported in the given snr, which is known even if the node
+// doesn't exist anymore
+func (r *SelfNodeRemediationReconciler) getNodeNameOfSnr(snr *v1alpha1.SelfNodeRemediation) (string, error) {
+	//SNR could be created by either machine based controller (e.g. MHC) or
+	//by a node based controller (e.g. NHC). This assumes that machine based controller
+	//will create the snr with machine owner reference
+
+	if machineRef := getMachineOwnerRef(snr); machineRef != nil {
+		gv, err := schema.ParseGroupVersion(machineRef.APIVersion)
+		if err != nil {
+			r.logger.Error(err, "failed to parse the API version of the machine owner ref", "api version", machineRef.APIVersion)
+			return "", err
+		}
+		if gv.Group == ClusterAPIMachineAPIGroup {
+			return r.getNodeNameFromClusterAPIMachine(*machineRef, snr.Namespace)
+		}
+		return r.getNodeNameFromMachine(*machineRef, snr.Namespace)
+	}
+
+	//since we didn't find a machine owner ref, we assume that snr name is the unhealthy node name
+	return snr.Name, nil
+}
+
+// getNodeNameFromClusterAPIMachine returns the name of the node referenced by the status of the given Cluster API Machine
+func (r *SelfNodeRemediationReconciler) getNodeNameFromClusterAPIMachine(ref metav1.OwnerReference, ns string) (string, error) {
+	machine := &unstructured.Unstructured{}
+	machine.SetAPIVersion(ref.APIVersion)
+	machine.SetKind(ref.Kind)
 	machineKey := client.ObjectKey{
 		Name:      ref.Name,
 		Namespace: ns,
@@ -675,28 +2396,40 @@ func (r *SelfNodeRemediationReconciler) getNodeFromMachine(ref metav1.OwnerRefer
 	if err := r.Client.Get(context.Background(), machineKey, machine); err != nil {
 		r.logger.Error(err, "failed to get machine from SelfNodeRemediation CR owner ref",
 			"machine name", machineKey.Name, "namespace", machineKey.Namespace)
-		return nil, err
+		return "", err
 	}
 
-	if machine.Status.NodeRef == nil {
-		err := errors.New("nodeRef is nil")
+	nodeName, _, err := unstructured.NestedString(machine.Object, "status", "nodeRef", "name")
+	if err != nil || nodeName == "" {
+		err = errors.New("nodeRef is nil")
 		r.logger.Error(err, "failed to retrieve node from the unhealthy machine")
-		return nil, err
+		return "", err
 	}
 
-	node := &v1.Node{}
-	key := client.ObjectKey{
-		Name:      machine.Status.NodeRef.Name,
-		Namespace: machine.Status.NodeRef.Namespace,
+	return nodeName, nil
+}
+
+// getNodeNameFromMachine returns the name of the node referenced by the status of the given Machine
+func (r *SelfNodeRemediationReconciler) getNodeNameFromMachine(ref metav1.OwnerReference, ns string) (string, error) {
+	machine := &v1beta1.Machine{}
+	machineKey := client.ObjectKey{
+		Name:      ref.Name,
+		Namespace: ns,
 	}
 
-	if err := r.Get(context.Background(), key, node); err != nil {
-		r.logger.Error(err, "failed to retrieve node from the unhealthy machine",
-			"node name", node.Name, "machine name", machine.Name)
-		return nil, err
+	if err := r.Client.Get(context.Background(), machineKey, machine); err != nil {
+		r.logger.Error(err, "failed to get machine from SelfNodeRemediation CR owner ref",
+			"machine name", machineKey.Name, "namespace", machineKey.Namespace)
+		return "", err
 	}
 
-	return node, nil
+	if machine.Status.NodeRef == nil {
+		err := errors.New("nodeRef is nil")
+		r.logger.Error(err, "failed to retrieve node from the unhealthy machine")
+		return "", err
+	}
+
+	return machine.Status.NodeRef.Name, nil
 }
 
 // the unhealthy node might reboot itself and take new workloads
@@ -725,8 +2458,7 @@ func (r *SelfNodeRemediationReconciler) markNodeAsUnschedulable(node *v1.Node) (
 func (r *SelfNodeRemediationReconciler) restoreNode(nodeToRestore *v1.Node) (ctrl.Result, error) {
 	r.logger.Info("restoring node", "node name", nodeToRestore.Name)
 
-	// todo we probably want to have some allowlist/denylist on which things to restore, we already had
-	// a problem when we restored ovn annotations
+	// node specific annotations, e.g. the ovn ones, are already filtered out when the backup is taken
 	nodeToRestore.ResourceVersion = "" //create won't work with a non-empty value here
 	taints, _ := utils.DeleteTaint(nodeToRestore.Spec.Taints, NodeUnschedulableTaint)
 	nodeToRestore.Spec.Taints = taints
@@ -869,12 +2601,12 @@ func (r *SelfNodeRemediationReconciler) removeOutOfServiceTaint(node *v1.Node) e
 
 func (r *SelfNodeRemediationReconciler) isResourceDeletionCompleted(node *v1.Node) bool {
 	pods := &v1.PodList{}
-	if err := r.Client.List(context.Background(), pods); err != nil {
+	if err := r.Client.List(context.Background(), pods, client.MatchingFields{podNodeNameField: node.Name}); err != nil {
 		r.logger.Error(err, "failed to get pod list")
 		return false
 	}
 	for _, pod := range pods.Items {
-		if pod.Spec.NodeName == node.Name && r.isPodTerminating(&pod) {
+		if r.isPodTerminating(&pod) {
 			r.logger.Info("waiting for terminating pod ", "pod name", pod.Name, "phase", pod.Status.Phase)
 			return false
 		}
@@ -130,8 +130,17 @@ func (r *SelfNodeRemediationConfigReconciler) syncConfigDaemonSet(ctx context.Co
 	data.Data["ApiServerTimeout"] = snrConfig.Spec.ApiServerTimeout.Nanoseconds()
 	data.Data["PeerDialTimeout"] = snrConfig.Spec.PeerDialTimeout.Nanoseconds()
 	data.Data["PeerRequestTimeout"] = snrConfig.Spec.PeerRequestTimeout.Nanoseconds()
+	data.Data["PeerAddressPreference"] = snrConfig.Spec.PeerAddressPreference
+	data.Data["PeerRackLabel"] = snrConfig.Spec.PeerRackLabel
+	data.Data["PeerQuorumPolicy"] = selfnoderemediationv1alpha1.FirstResponsePeerQuorumPolicy
+	data.Data["PeerQuorumMinAgreeingPeers"] = 0
+	if quorum := snrConfig.Spec.PeerQuorum; quorum != nil {
+		data.Data["PeerQuorumPolicy"] = quorum.Policy
+		data.Data["PeerQuorumMinAgreeingPeers"] = quorum.MinAgreeingPeers
+	}
 	data.Data["MaxApiErrorThreshold"] = snrConfig.Spec.MaxApiErrorThreshold
 	data.Data["EndpointHealthCheckUrl"] = snrConfig.Spec.EndpointHealthCheckUrl
+	data.Data["KubeletFailureThreshold"] = snrConfig.Spec.KubeletFailureThreshold
 	data.Data["HostPort"] = snrConfig.Spec.HostPort
 
 	safeTimeToAssumeNodeRebootedSeconds := snrConfig.Spec.SafeTimeToAssumeNodeRebootedSeconds
@@ -210,6 +210,8 @@ var _ = Describe("SNR Config Test", func() {
 			Expect(createdConfig.Spec.WatchdogFilePath).To(Equal("/dev/watchdog"))
 			Expect(createdConfig.Spec.SafeTimeToAssumeNodeRebootedSeconds).To(Equal(180))
 			Expect(createdConfig.Spec.MaxApiErrorThreshold).To(Equal(3))
+			Expect(createdConfig.Spec.KubeletFailureThreshold).To(Equal(3))
+			Expect(createdConfig.Spec.PeerAddressPreference).To(Equal(selfnoderemediationv1alpha1.InternalIPPeerAddressPreference))
 
 			Expect(createdConfig.Spec.PeerApiServerTimeout.Seconds()).To(BeEquivalentTo(5))
 			Expect(createdConfig.Spec.PeerRequestTimeout.Seconds()).To(BeEquivalentTo(5))
@@ -113,7 +113,7 @@ var _ = BeforeSuite(func() {
 	Expect(k8sClient.Create(context.Background(), peerNode)).To(Succeed(), "failed to create peer node")
 
 	peerApiServerTimeout := 5 * time.Second
-	peers := peers.New(shared.UnhealthyNodeName, shared.PeerUpdateInterval, k8sClient, ctrl.Log.WithName("peers"), peerApiServerTimeout)
+	peers := peers.New(shared.UnhealthyNodeName, shared.PeerUpdateInterval, k8sClient, ctrl.Log.WithName("peers"), peerApiServerTimeout, "")
 	err = k8sManager.Add(peers)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -14,11 +15,14 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/medik8s/self-node-remediation/api/v1alpha1"
 	"github.com/medik8s/self-node-remediation/controllers"
@@ -61,6 +65,7 @@ var _ = Describe("SNR Controller", func() {
 	AfterEach(func() {
 		k8sClient.ShouldSimulateFailure = false
 		k8sClient.ShouldSimulatePodDeleteFailure = false
+		k8sClient.ShouldSimulateForbiddenCreate = false
 		isAdditionalSetupNeeded = false
 		deleteRemediations()
 		deleteSelfNodeRemediationPod()
@@ -183,15 +188,965 @@ var _ = Describe("SNR Controller", func() {
 
 				verifySelfNodeRemediationPodDoesntExist()
 
+				verifyVaDeleted(vaName)
+
 				verifyEvent("Normal", "DeleteResources", "Remediation process - finished deleting unhealthy node resources")
 
 				verifyFinalizerExists()
 
-				verifyEvent("Normal", "AddFinalizer", "Remediation process - successful adding finalizer")
+				verifyEvent("Normal", "AddFinalizer", "Remediation process - successful adding finalizer")
+
+				verifyNoExecuteTaintExist()
+
+				verifyEvent("Normal", "AddNoExecute", "Remediation process - NoExecute taint added to the unhealthy node")
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				verifyMetricExists("self_node_remediation_remediations_total")
+
+				verifyMetricExists("self_node_remediation_phase_duration_seconds")
+
+				verifyMetricExists("self_node_remediation_reboots_total")
+
+				verifyTimelinePhases("Fencing-Started", "Pre-Reboot-Completed", "Reboot-Completed", "Fencing-Completed")
+
+				deleteSNR(snr)
+
+				verifyNodeIsSchedulable()
+
+				removeUnschedulableTaint()
+
+				verifyEvent("Normal", "MarkNodeSchedulable", "Remediation process - mark healthy remediated node as schedulable")
+
+				verifyNoExecuteTaintRemoved()
+
+				verifyEvent("Normal", "RemoveNoExecuteTaint", "Remediation process - remove NoExecute taint from healthy remediated node")
+
+				verifyEvent("Normal", "RemoveFinalizer", "Remediation process - remove finalizer from snr")
+
+				verifySNRDoesNotExists()
+
+			})
+
+			It("The snr agent attempts to keep deleting node resources during temporary api-server failure", func() {
+				node := verifyNodeIsUnschedulable()
+
+				k8sClient.ShouldSimulatePodDeleteFailure = true
+
+				addUnschedulableTaint(node)
+
+				verifyTypeConditions(snr.Name, metav1.ConditionTrue, metav1.ConditionUnknown, "RemediationStarted")
+
+				verifyTimeHasBeenRebootedExists()
+
+				verifyNoWatchdogFood()
+
+				verifyVaNotDeleted(vaName)
+
+				// The kube-api calls for VA fail intentionally. In this case, we expect the snr agent to try
+				// to delete node resources again. So LastError is set to the error every time Reconcile()
+				// is triggered. If it becomes another error, it means something unintended happens.
+				verifyLastErrorKeepsApiError()
+
+				k8sClient.ShouldSimulatePodDeleteFailure = false
+
+				verifySelfNodeRemediationPodDoesntExist()
+
+				verifyVaDeleted(vaName)
+
+				deleteSNR(snr)
+
+				removeUnschedulableTaint()
+
+				verifySNRDoesNotExists()
+
+			})
+			When("Node isn't found", func() {
+				BeforeEach(func() {
+					snr.Name = "non-existing-node"
+				})
+
+				It("remediation should stop and update conditions", func() {
+					verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionFalse, "RemediationFinishedNodeNotFound")
+
+					verifyEvent("Normal", "RemediationStopped", "couldn't find node matching remediation")
+				})
+			})
+		})
+
+		Context("Pod deletion annotations", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				createPodOnUnhealthyNode("excluded-pod", map[string]string{v1alpha1.ExcludeFromDeletionAnnotation: "true"})
+				createPodOnUnhealthyNode("prioritized-pod", map[string]string{v1alpha1.DeletionPriorityAnnotation: "10"})
+			})
+
+			It("should delete pods by priority, skip excluded pods and report the result of each pod", func() {
+				node := verifyNodeIsUnschedulable()
+
+				addUnschedulableTaint(node)
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				verifySelfNodeRemediationPodDoesntExist()
+
+				By("Verify that the excluded pod wasn't deleted")
+				Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "excluded-pod", Namespace: shared.Namespace}, &v1.Pod{})).To(Succeed())
+
+				By("Verify that the result of each pod was reported")
+				updatedSnr := &v1alpha1.SelfNodeRemediation{}
+				Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), updatedSnr)).To(Succeed())
+				Expect(updatedSnr.Status.PodDeletions).To(ConsistOf(
+					v1alpha1.PodDeletion{Namespace: shared.Namespace, Name: "prioritized-pod", Priority: 10, Result: v1alpha1.PodDeleted},
+					v1alpha1.PodDeletion{Namespace: shared.Namespace, Name: "self-node-remediation", Result: v1alpha1.PodDeleted},
+					v1alpha1.PodDeletion{Namespace: shared.Namespace, Name: "excluded-pod", Result: v1alpha1.PodDeletionExcluded},
+				))
+				Expect(updatedSnr.Status.PodDeletions[0].Name).To(Equal("prioritized-pod"))
+
+				deleteSNR(snr)
+
+				verifyNodeIsSchedulable()
+
+				removeUnschedulableTaint()
+
+				verifySNRDoesNotExists()
+			})
+		})
+
+		Context("Drain before fencing", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				snr.Spec.DrainTimeout = &metav1.Duration{Duration: 3 * time.Second}
+			})
+
+			It("should cordon the node, evict the pods and fence the node when the drain times out", func() {
+				// same as kubectl drain, the node is cordoned before the pods are evicted
+				verifyNodeIsUnschedulable()
+
+				By("Verify that the pod isn't evicted before the node is cordoned")
+				Consistently(func() (bool, error) {
+					pod := &v1.Pod{}
+					err := k8sClient.Get(context.Background(), client.ObjectKey{Name: "self-node-remediation", Namespace: shared.Namespace}, pod)
+					return pod.DeletionTimestamp == nil, err
+				}, 2*time.Second, 250*time.Millisecond).Should(BeTrue())
+
+				addUnschedulableTaint(verifyNodeIsUnschedulable())
+
+				By("Verify that the pod was evicted")
+				Eventually(func() (bool, error) {
+					pod := &v1.Pod{}
+					err := k8sClient.Get(context.Background(), client.ObjectKey{Name: "self-node-remediation", Namespace: shared.Namespace}, pod)
+					return pod.DeletionTimestamp != nil, err
+				}, 10*time.Second, 250*time.Millisecond).Should(BeTrue())
+
+				verifyDrainState(v1alpha1.DrainInProgress)
+
+				// the evicted pod stays terminating without a kubelet, so the drain can't complete
+				verifyDrainState(v1alpha1.DrainTimedOut)
+
+				verifyEvent("Normal", "DrainTimedOut", "Remediation process - drain timed out with 1 pods which weren't evicted")
+
+				verifyNoExecuteTaintExist()
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				deleteSNR(snr)
+
+				verifyNodeIsSchedulable()
+
+				removeUnschedulableTaint()
+
+				verifySNRDoesNotExists()
+			})
+		})
+
+		Context("Dry run", func() {
+			var remediationsTotal float64
+
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				snr.Spec.DryRun = true
+				remediationsTotal = getRemediationsTotal()
+			})
+
+			It("should walk through all phases without changing the node", func() {
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				By("Verify that the dry run isn't counted as a finished remediation")
+				Expect(getRemediationsTotal()).To(Equal(remediationsTotal))
+
+				verifyTimelinePhases("Fencing-Started", "Pre-Reboot-Completed", "Reboot-Completed", "Fencing-Completed")
+
+				verifyTimeHasBeenRebootedExists()
+
+				verifyEvent("Normal", "DryRun", "Remediation process (dry run) - NoExecute taint would be added to the unhealthy node")
+
+				verifyEvent("Normal", "DryRun", "Remediation process (dry run) - unhealthy node would be rebooted")
+
+				verifyEvent("Normal", "DryRun", fmt.Sprintf("Remediation process (dry run) - pod %s/self-node-remediation would be deleted", shared.Namespace))
+
+				verifyEvent("Normal", "DryRun", fmt.Sprintf("Remediation process (dry run) - volumeAttachment %s would be deleted", vaName))
+
+				verifyNoExecuteTaintRemoved()
+
+				verifyNodeIsSchedulable()
+
+				verifySelfNodeRemediationPodExist()
+
+				verifyVaNotDeleted(vaName)
+
+				deleteSNR(snr)
+
+				verifySNRDoesNotExists()
+			})
+		})
+
+		Context("Escalation", func() {
+			var template *unstructured.Unstructured
+
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				template = &unstructured.Unstructured{Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{"foo": "bar"},
+						},
+					},
+				}}
+				template.SetAPIVersion("test.medik8s.io/v1alpha1")
+				template.SetKind("TestRemediationTemplate")
+				template.SetName("test-escalation-template")
+				template.SetNamespace(snrNamespace)
+				Expect(k8sClient.Create(context.Background(), template)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), template)).To(Succeed())
+				})
+
+				snr.Spec.Escalation = &v1alpha1.Escalation{
+					RemediationTemplate: v1alpha1.EscalationTemplateReference{
+						APIVersion: template.GetAPIVersion(),
+						Kind:       template.GetKind(),
+						Name:       template.GetName(),
+					},
+					Timeout: metav1.Duration{Duration: time.Second},
+				}
+			})
+
+			It("should create the secondary remediation when the node isn't Ready after fencing was completed", func() {
+				node := verifyNodeIsUnschedulable()
+
+				addUnschedulableTaint(node)
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				By("Verify that the secondary remediation was created")
+				remediation := &unstructured.Unstructured{}
+				remediation.SetAPIVersion("test.medik8s.io/v1alpha1")
+				remediation.SetKind("TestRemediation")
+				Eventually(func() error {
+					return k8sClient.Get(context.Background(), client.ObjectKey{Name: snr.Name, Namespace: snrNamespace}, remediation)
+				}, 10*time.Second, 250*time.Millisecond).Should(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), remediation)).To(Succeed())
+				})
+				foo, _, err := unstructured.NestedString(remediation.Object, "spec", "foo")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(foo).To(Equal("bar"))
+				Expect(remediation.GetOwnerReferences()).To(HaveLen(1))
+				Expect(remediation.GetOwnerReferences()[0].Kind).To(Equal("SelfNodeRemediation"))
+
+				By("Verify that the SNR was marked as escalated")
+				Eventually(func() (bool, error) {
+					updatedSnr := &v1alpha1.SelfNodeRemediation{}
+					err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), updatedSnr)
+					return meta.IsStatusConditionTrue(updatedSnr.Status.Conditions, v1alpha1.EscalatedConditionType), err
+				}, 10*time.Second, 250*time.Millisecond).Should(BeTrue())
+
+				verifyEvent("Normal", "RemediationEscalated", "Remediation process - node isn't Ready 1s after fencing was completed, created TestRemediation "+snr.Name)
+
+				deleteSNR(snr)
+
+				verifyNodeIsSchedulable()
+
+				removeUnschedulableTaint()
+
+				verifySNRDoesNotExists()
+			})
+
+			Context("without permissions to create the secondary remediation", func() {
+				BeforeEach(func() {
+					k8sClient.ShouldSimulateForbiddenCreate = true
+				})
+
+				It("should mark the SNR as not escalated instead of retrying", func() {
+					node := verifyNodeIsUnschedulable()
+
+					addUnschedulableTaint(node)
+
+					verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+					By("Verify that the SNR was marked as not escalated")
+					Eventually(func() (string, error) {
+						updatedSnr := &v1alpha1.SelfNodeRemediation{}
+						if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), updatedSnr); err != nil {
+							return "", err
+						}
+						escalatedCond := meta.FindStatusCondition(updatedSnr.Status.Conditions, v1alpha1.EscalatedConditionType)
+						if escalatedCond == nil || escalatedCond.Status != metav1.ConditionFalse {
+							return "", nil
+						}
+						return escalatedCond.Reason, nil
+					}, 10*time.Second, 250*time.Millisecond).Should(Equal("EscalationForbidden"))
+
+					By("Verify that the secondary remediation wasn't created")
+					remediation := &unstructured.Unstructured{}
+					remediation.SetAPIVersion("test.medik8s.io/v1alpha1")
+					remediation.SetKind("TestRemediation")
+					err := k8sClient.Get(context.Background(), client.ObjectKey{Name: snr.Name, Namespace: snrNamespace}, remediation)
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+					deleteSNR(snr)
+
+					verifyNodeIsSchedulable()
+
+					removeUnschedulableTaint()
+
+					verifySNRDoesNotExists()
+				})
+			})
+		})
+
+		Context("VolumeAttachment finalizer policies", func() {
+			const vaWithFinalizerName = "va-with-finalizer"
+			var attacher string
+
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				config := &v1alpha1.SelfNodeRemediationConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigCRName, Namespace: shared.Namespace},
+					Spec: v1alpha1.SelfNodeRemediationConfigSpec{
+						VolumeAttachmentFinalizerPolicies: []v1alpha1.VolumeAttachmentFinalizerPolicy{
+							{Driver: "force-detach.csi.test", Policy: v1alpha1.RemoveVolumeAttachmentFinalizerPolicy},
+						},
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), config)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), config)).To(Succeed())
+				})
+			})
+
+			JustBeforeEach(func() {
+				va := &storagev1.VolumeAttachment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       vaWithFinalizerName,
+						Finalizers: []string{"external-attacher/" + attacher},
+					},
+					Spec: storagev1.VolumeAttachmentSpec{
+						Attacher: attacher,
+						NodeName: shared.UnhealthyNodeName,
+					},
+				}
+				pvName := "some-pv"
+				va.Spec.Source.PersistentVolumeName = &pvName
+				Expect(k8sClient.Create(context.Background(), va)).To(Succeed())
+				DeferCleanup(deleteVolumeAttachment, vaWithFinalizerName, false)
+				DeferCleanup(removeVaFinalizers, vaWithFinalizerName)
+			})
+
+			When("the CSI driver finalizer policy is Remove", func() {
+				BeforeEach(func() {
+					attacher = "force-detach.csi.test"
+				})
+
+				It("should remove the finalizers of the deleted volumeAttachment", func() {
+					node := verifyNodeIsUnschedulable()
+
+					addUnschedulableTaint(node)
+
+					verifyTimeHasBeenRebootedExists()
+
+					verifySelfNodeRemediationPodDoesntExist()
+
+					verifyVaDeleted(vaWithFinalizerName)
+
+					verifyVaDeleted(vaName)
+
+					verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+				})
+			})
+
+			When("the CSI driver has no finalizer policy", func() {
+				BeforeEach(func() {
+					attacher = "wait.csi.test"
+				})
+
+				It("should wait for the CSI driver to remove the finalizers of the deleted volumeAttachment", func() {
+					node := verifyNodeIsUnschedulable()
+
+					addUnschedulableTaint(node)
+
+					verifyTimeHasBeenRebootedExists()
+
+					verifyVaDeleted(vaName)
+
+					By("Verify that fencing isn't completed while the volumeAttachment still exists")
+					Consistently(func() (bool, error) {
+						va := &storagev1.VolumeAttachment{}
+						if err := k8sClient.Get(context.Background(), client.ObjectKey{Name: vaWithFinalizerName}, va); err != nil {
+							return false, err
+						}
+						return va.DeletionTimestamp != nil, nil
+					}, 5*time.Second, 250*time.Millisecond).Should(BeTrue())
+					verifyTypeConditions(snr.Name, metav1.ConditionTrue, metav1.ConditionUnknown, "RemediationStarted")
+
+					By("Simulate the CSI driver detaching the volume")
+					removeVaFinalizers(vaWithFinalizerName)
+
+					verifyVaDeleted(vaWithFinalizerName)
+
+					// the remediation checks again for the volumeAttachment only after its requeue interval
+					Eventually(func() (bool, error) {
+						snrKey := client.ObjectKey{Name: snr.Name, Namespace: snrNamespace}
+						if err := k8sClient.Get(context.Background(), snrKey, snr); err != nil {
+							return false, err
+						}
+						return meta.IsStatusConditionTrue(snr.Status.Conditions, v1alpha1.SucceededConditionType), nil
+					}, 10*time.Second, 250*time.Millisecond).Should(BeTrue())
+				})
+			})
+		})
+
+		Context("Blackout window active", func() {
+			var config *v1alpha1.SelfNodeRemediationConfig
+
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				config = &v1alpha1.SelfNodeRemediationConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigCRName, Namespace: shared.Namespace},
+					Spec: v1alpha1.SelfNodeRemediationConfigSpec{
+						BlackoutWindows: []v1alpha1.BlackoutWindow{
+							{
+								Name:         "freeze",
+								Schedule:     "* * * * *",
+								Duration:     metav1.Duration{Duration: time.Hour},
+								NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/hostname": shared.UnhealthyNodeName}},
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), config)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), config)).To(Succeed())
+				})
+			})
+
+			It("should postpone the remediation until the window ends", func() {
+				verifyPostponedCondition(metav1.ConditionTrue)
+
+				Consistently(func() bool {
+					snrKey := client.ObjectKey{Name: snr.Name, Namespace: snrNamespace}
+					if err := k8sClient.Get(context.Background(), snrKey, snr); err != nil {
+						return true
+					}
+					return controllerutil.ContainsFinalizer(snr, controllers.SNRFinalizer)
+				}, 3*time.Second, 250*time.Millisecond).Should(BeFalse())
+				Expect(meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.PostponedConditionType).Message).To(ContainSubstring("blackout window freeze is active until"))
+
+				By("End the blackout window")
+				Eventually(func() error {
+					if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(config), config); err != nil {
+						return err
+					}
+					config.Spec.BlackoutWindows[0].NodeSelector.MatchLabels["kubernetes.io/hostname"] = shared.PeerNodeName
+					return k8sClient.Update(context.Background(), config)
+				}, 5*time.Second, 250*time.Millisecond).Should(Succeed())
+
+				verifyPostponedCondition(metav1.ConditionFalse)
+
+				verifyEvent("Normal", "RemediationResumed", "Remediation process - blackout window ended, resuming remediation")
+
+				verifyFinalizerExists()
+
+				verifyTimelinePhases("Postponed", "Fencing-Started")
+			})
+		})
+
+		Context("Paused by the config", func() {
+			var config *v1alpha1.SelfNodeRemediationConfig
+
+			setPaused := func(isPaused bool) {
+				Eventually(func() error {
+					if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(config), config); err != nil {
+						return err
+					}
+					config.Spec.Paused = isPaused
+					return k8sClient.Update(context.Background(), config)
+				}, 5*time.Second, 250*time.Millisecond).Should(Succeed())
+			}
+
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				config = &v1alpha1.SelfNodeRemediationConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigCRName, Namespace: shared.Namespace},
+					Spec:       v1alpha1.SelfNodeRemediationConfigSpec{Paused: true},
+				}
+				Expect(k8sClient.Create(context.Background(), config)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), config)).To(Succeed())
+				})
+			})
+
+			It("should not advance the remediation until it's unpaused", func() {
+				verifyPausedCondition(metav1.ConditionTrue)
+
+				verifyEvent("Normal", "RemediationPaused", "Remediation process - remediation is paused by the SelfNodeRemediationConfig")
+
+				Consistently(func() bool {
+					if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), snr); err != nil {
+						return true
+					}
+					return controllerutil.ContainsFinalizer(snr, controllers.SNRFinalizer) || snr.Status.Phase != nil
+				}, 3*time.Second, 250*time.Millisecond).Should(BeFalse())
+
+				By("Unpause the remediation")
+				setPaused(false)
+
+				verifyPausedCondition(metav1.ConditionFalse)
+
+				verifyEvent("Normal", "RemediationResumed", "Remediation process - remediation was unpaused, resuming remediation")
+
+				verifyNodeIsUnschedulable()
+			})
+
+			It("should clean up a deleted remediation while paused", func() {
+				verifyPausedCondition(metav1.ConditionTrue)
+
+				setPaused(false)
+
+				node := verifyNodeIsUnschedulable()
+
+				addUnschedulableTaint(node)
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				By("Pause the remediation again and delete the snr")
+				setPaused(true)
+
+				verifyPausedCondition(metav1.ConditionTrue)
+
+				deleteSNR(snr)
+
+				verifyNodeIsSchedulable()
+
+				removeUnschedulableTaint()
+
+				verifySNRDoesNotExists()
+			})
+		})
+
+		Context("Zone failure suspected", func() {
+			var peerSnr *v1alpha1.SelfNodeRemediation
+
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				config := &v1alpha1.SelfNodeRemediationConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigCRName, Namespace: shared.Namespace},
+					Spec: v1alpha1.SelfNodeRemediationConfigSpec{
+						ZoneFailureDetection: &v1alpha1.ZoneFailureDetection{UnhealthyNodesPercentage: 100, TimeWindow: metav1.Duration{Duration: time.Minute}},
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), config)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), config)).To(Succeed())
+				})
+
+				for _, nodeName := range []string{shared.UnhealthyNodeName, shared.PeerNodeName} {
+					node := &v1.Node{}
+					Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: nodeName}, node)).To(Succeed())
+					node.Labels[v1.LabelTopologyZone] = "zone-a"
+					Expect(k8sClient.Update(context.Background(), node)).To(Succeed())
+				}
+
+				peerSnr = &v1alpha1.SelfNodeRemediation{}
+				peerSnr.Name = shared.PeerNodeName
+				peerSnr.Namespace = snrNamespace
+				createSNR(peerSnr, v1alpha1.ResourceDeletionRemediationStrategy)
+			})
+
+			It("should pause fencing a zone of 2 nodes while both of them are unhealthy at 100%", func() {
+				verifyZoneFailureSuspectedCondition(metav1.ConditionTrue)
+
+				verifyEvent("Warning", "ZoneFailureSuspected", "Remediation process - fencing paused: 2 of 2 nodes in zone zone-a need remediation within 1m0s")
+
+				Consistently(func() bool {
+					if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), snr); err != nil {
+						return true
+					}
+					return controllerutil.ContainsFinalizer(snr, controllers.SNRFinalizer)
+				}, 3*time.Second, 250*time.Millisecond).Should(BeFalse())
+
+				By("Recover the other node of the zone")
+				deleteSNR(peerSnr)
+
+				verifyZoneFailureSuspectedCondition(metav1.ConditionFalse)
+
+				verifyEvent("Normal", "RemediationResumed", "Remediation process - zone failure no longer suspected, resuming remediation")
+
+				verifyNodeIsUnschedulable()
+			})
+		})
+
+		Context("Zone failure not suspected for a single node", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				config := &v1alpha1.SelfNodeRemediationConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigCRName, Namespace: shared.Namespace},
+					Spec: v1alpha1.SelfNodeRemediationConfigSpec{
+						ZoneFailureDetection: &v1alpha1.ZoneFailureDetection{UnhealthyNodesPercentage: 100, TimeWindow: metav1.Duration{Duration: time.Minute}},
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), config)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), config)).To(Succeed())
+				})
+
+				node := &v1.Node{}
+				Expect(k8sClient.Get(context.Background(), unhealthyNodeNamespacedName, node)).To(Succeed())
+				node.Labels[v1.LabelTopologyZone] = "zone-b"
+				Expect(k8sClient.Update(context.Background(), node)).To(Succeed())
+			})
+
+			It("should fence the only node of its zone at 100%", func() {
+				verifyZoneFailureSuspectedCondition(metav1.ConditionFalse)
+
+				verifyNodeIsUnschedulable()
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+			})
+		})
+
+		Context("Reboot loop threshold", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				config := &v1alpha1.SelfNodeRemediationConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigCRName, Namespace: shared.Namespace},
+					Spec: v1alpha1.SelfNodeRemediationConfigSpec{
+						RebootLoopThreshold: &v1alpha1.RebootLoopThreshold{MaxRemediations: 2, TimeWindow: metav1.Duration{Duration: time.Hour}},
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), config)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), config)).To(Succeed())
+				})
+			})
+
+			When("the node was remediated less times than the threshold", func() {
+				BeforeEach(func() {
+					setRemediationHistory(1)
+				})
+
+				It("should remediate the node and add the remediation to the node history", func() {
+					verifyFinalizerExists()
+
+					Eventually(func() ([]string, error) {
+						node := &v1.Node{}
+						if err := k8sClient.Get(context.Background(), unhealthyNodeNamespacedName, node); err != nil {
+							return nil, err
+						}
+						return strings.Split(node.Annotations[v1alpha1.RemediationHistoryAnnotation], ","), nil
+					}, 5*time.Second, 250*time.Millisecond).Should(HaveLen(3))
+				})
+			})
+
+			When("the node crossed the threshold", func() {
+				BeforeEach(func() {
+					setRemediationHistory(2)
+				})
+
+				It("should refuse to remediate the node and leave it cordoned", func() {
+					verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionFalse, "RemediationPermanentlyFailed")
+
+					Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), snr)).To(Succeed())
+					Expect(meta.IsStatusConditionTrue(snr.Status.Conditions, v1alpha1.PermanentlyFailedConditionType)).To(BeTrue())
+					Expect(controllerutil.ContainsFinalizer(snr, controllers.SNRFinalizer)).To(BeFalse())
+
+					verifyEvent("Warning", "RemediationRefused", "Remediation process - remediation refused: node was already remediated 2 times within 1h0m0s, which exceeds the reboot loop threshold. Leaving the node cordoned for manual investigation")
+
+					verifyNodeIsUnschedulable()
+
+					verifyNoExecuteTaintRemoved()
+				})
+			})
+		})
+
+		Context("Recovery verification", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				snr.Spec.RecoveryVerificationTimeout = &metav1.Duration{Duration: time.Minute}
+				setNodeStatus("boot-before-fencing", v1.ConditionFalse)
+			})
+
+			AfterEach(func() {
+				eventuallyUpdateNode(func(node *v1.Node) {
+					node.Status = v1.NodeStatus{}
+				}, true)
+			})
+
+			It("should report that the node recovered once it's Ready with a new boot ID", func() {
+				node := verifyNodeIsUnschedulable()
+
+				addUnschedulableTaint(node)
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				verifyNodeRecoveredCondition(metav1.ConditionUnknown)
+
+				By("Simulate the node coming back after the reboot")
+				setNodeStatus("boot-after-fencing", v1.ConditionTrue)
+
+				verifyNodeRecoveredCondition(metav1.ConditionTrue)
+
+				verifyEvent("Normal", "NodeRecovered", "Remediation process - node is Ready after it was rebooted")
+
+				verifyTimelinePhases("Fencing-Started", "Pre-Reboot-Completed", "Reboot-Completed", "Fencing-Completed", "Verifying-Recovery")
+
+				Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), snr)).To(Succeed())
+				Expect(snr.Status.BootIDBeforeFencing).To(Equal("boot-before-fencing"))
+			})
+		})
+
+		Context("Node cordoned before the remediation", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				eventuallyUpdateNode(func(node *v1.Node) {
+					node.Spec.Unschedulable = true
+					node.Spec.Taints = append(node.Spec.Taints, *controllers.NodeUnschedulableTaint)
+				}, false)
+			})
+
+			It("should keep the node cordoned after the remediation", func() {
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), snr)).To(Succeed())
+				Expect(snr.Status.PreRemediationNodeState).To(Equal(&v1alpha1.NodeState{Unschedulable: true}))
+
+				deleteSNR(snr)
+
+				verifyNoExecuteTaintRemoved()
+
+				verifySNRDoesNotExists()
+
+				node := &v1.Node{}
+				Expect(k8sClient.Get(context.Background(), unhealthyNodeNamespacedName, node)).To(Succeed())
+				Expect(node.Spec.Unschedulable).To(BeTrue())
+				Expect(utils.TaintExists(node.Spec.Taints, controllers.NodeUnschedulableTaint)).To(BeTrue())
+			})
+		})
+
+		Context("Reboot approval", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+			})
+
+			When("the reboot is approved", func() {
+				BeforeEach(func() {
+					snr.Spec.RebootApproval = &v1alpha1.RebootApproval{}
+				})
+
+				It("should wait for the approval before rebooting the node", func() {
+					node := verifyNodeIsUnschedulable()
+
+					addUnschedulableTaint(node)
+
+					verifyRebootApprovedCondition(metav1.ConditionUnknown)
+
+					verifyEvent("Normal", "RemediationAwaitingApproval", "Remediation process - waiting for the approval of rebooting the unhealthy node")
+
+					Consistently(func() ([]string, error) {
+						if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), snr); err != nil {
+							return nil, err
+						}
+						var phases []string
+						for _, transition := range snr.Status.Timeline {
+							phases = append(phases, transition.Phase)
+						}
+						return phases, nil
+					}, 3*time.Second, 250*time.Millisecond).ShouldNot(ContainElement("Reboot-Completed"))
+
+					By("Approve the reboot")
+					Eventually(func() error {
+						if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), snr); err != nil {
+							return err
+						}
+						snr.Annotations = map[string]string{v1alpha1.RebootApprovedAnnotation: "true"}
+						return k8sClient.Update(context.Background(), snr)
+					}, 5*time.Second, 250*time.Millisecond).Should(Succeed())
+
+					verifyRebootApprovedCondition(metav1.ConditionTrue)
+
+					verifyEvent("Normal", "RebootApproved", "Remediation process - reboot was approved by the reboot-approved annotation")
+
+					verifyNoWatchdogFood()
+
+					verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+				})
+			})
+
+			When("the approval times out", func() {
+				BeforeEach(func() {
+					snr.Spec.RebootApproval = &v1alpha1.RebootApproval{
+						Timeout:       &metav1.Duration{Duration: time.Second},
+						TimeoutAction: v1alpha1.AbortRebootApprovalTimeoutAction,
+					}
+				})
+
+				It("should abort the remediation and leave the node fenced", func() {
+					node := verifyNodeIsUnschedulable()
+
+					addUnschedulableTaint(node)
+
+					verifyRebootApprovedCondition(metav1.ConditionFalse)
+
+					verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionFalse, "RemediationAborted")
+
+					verifyEvent("Warning", "RemediationAborted", "Remediation process - remediation aborted: reboot wasn't approved within 1s, leaving the node fenced")
+
+					verifyNodeIsUnschedulable()
+				})
+			})
+		})
+
+		Context("Node rebooted before the time it's assumed to be rebooted", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				setNodeStatus("boot-before-fencing", v1.ConditionFalse)
+			})
+
+			AfterEach(func() {
+				eventuallyUpdateNode(func(node *v1.Node) {
+					node.Status = v1.NodeStatus{}
+				}, true)
+			})
+
+			It("should complete the reboot once the node is Ready with a new boot ID", func() {
+				node := verifyNodeIsUnschedulable()
+
+				addUnschedulableTaint(node)
+
+				verifyTimeHasBeenRebootedExists()
+
+				By("Simulate the node coming back after the reboot")
+				setNodeStatus("boot-after-fencing", v1.ConditionTrue)
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(snr), snr)).To(Succeed())
+				Expect(snr.Status.BootIDBeforeFencing).To(Equal("boot-before-fencing"))
+				Expect(snr.Status.Timeline).To(ContainElement(And(
+					HaveField("Phase", "Reboot-Completed"),
+					HaveField("Reason", "NodeBootIDChanged"),
+				)))
+			})
+		})
+
+		Context("Cluster API Machine owner", func() {
+			const machineName = "unhealthy-node-machine"
+
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				machine := &unstructured.Unstructured{}
+				machine.SetAPIVersion("cluster.x-k8s.io/v1beta1")
+				machine.SetKind("Machine")
+				machine.SetName(machineName)
+				machine.SetNamespace(snrNamespace)
+				Expect(unstructured.SetNestedField(machine.Object, shared.UnhealthyNodeName, "status", "nodeRef", "name")).To(Succeed())
+				Expect(k8sClient.Create(context.Background(), machine)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), machine)).To(Succeed())
+				})
+
+				snr.Name = machineName
+				snr.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: machine.GetAPIVersion(),
+					Kind:       machine.GetKind(),
+					Name:       machine.GetName(),
+					UID:        machine.GetUID(),
+				}}
+			})
+
+			It("should remediate the node referenced by the machine", func() {
+				verifyNodeIsUnschedulable()
+
+				verifyEvent("Normal", "MarkUnschedulable", "Remediation process - unhealthy node marked as unschedulable")
+			})
+		})
+
+		Context("Max concurrent fencing reached", func() {
+			var config *v1alpha1.SelfNodeRemediationConfig
+
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.ResourceDeletionRemediationStrategy
+				maxConcurrentFencing := intstr.FromInt(1)
+				config = &v1alpha1.SelfNodeRemediationConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.ConfigCRName, Namespace: shared.Namespace},
+					Spec:       v1alpha1.SelfNodeRemediationConfigSpec{MaxConcurrentFencing: &maxConcurrentFencing},
+				}
+				Expect(k8sClient.Create(context.Background(), config)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), config)).To(Succeed())
+				})
+
+				By("Create the remediation of another node which is already fenced")
+				fencedSnr := &v1alpha1.SelfNodeRemediation{}
+				fencedSnr.Name = "fenced-node"
+				fencedSnr.Namespace = snrNamespace
+				fencedSnr.Finalizers = []string{controllers.SNRFinalizer}
+				createSNR(fencedSnr, v1alpha1.ResourceDeletionRemediationStrategy)
+			})
+
+			It("should wait until the limit allows fencing the node", func() {
+				verifyWaitingCondition(metav1.ConditionTrue)
+
+				verifyEvent("Normal", "RemediationWaiting", "Remediation process - waiting for other remediations to finish: 1 nodes are already being fenced, which is the max concurrent fencing limit")
+
+				verifyNoExecuteTaintRemoved()
+
+				By("Raise the max concurrent fencing limit")
+				Eventually(func() error {
+					if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(config), config); err != nil {
+						return err
+					}
+					maxConcurrentFencing := intstr.FromInt(2)
+					config.Spec.MaxConcurrentFencing = &maxConcurrentFencing
+					return k8sClient.Update(context.Background(), config)
+				}, 5*time.Second, 250*time.Millisecond).Should(Succeed())
+
+				verifyWaitingCondition(metav1.ConditionFalse)
+
+				verifyFinalizerExists()
+
+				verifyTimelinePhases("Waiting", "Fencing-Started")
+			})
+		})
 
-				verifyNoExecuteTaintExist()
+		Context("Automatic strategy", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.AutomaticRemediationStrategy
+			})
 
-				verifyEvent("Normal", "AddNoExecute", "Remediation process - NoExecute taint added to the unhealthy node")
+			It("should resolve to ResourceDeletion when out of service taint isn't supported", func() {
+				verifyRemediationStrategyInStatus(v1alpha1.ResourceDeletionRemediationStrategy)
+
+				node := verifyNodeIsUnschedulable()
+
+				addUnschedulableTaint(node)
+
+				verifyTimeHasBeenRebootedExists()
+
+				verifyNoWatchdogFood()
+
+				verifySelfNodeRemediationPodDoesntExist()
 
 				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
 
@@ -201,60 +1156,121 @@ var _ = Describe("SNR Controller", func() {
 
 				removeUnschedulableTaint()
 
-				verifyEvent("Normal", "MarkNodeSchedulable", "Remediation process - mark healthy remediated node as schedulable")
+				verifySNRDoesNotExists()
+			})
+		})
 
-				verifyNoExecuteTaintRemoved()
+		Context("NodeDeletion strategy", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.NodeDeletionRemediationStrategy
+				eventuallyUpdateNode(func(node *v1.Node) {
+					if node.Annotations == nil {
+						node.Annotations = map[string]string{}
+					}
+					node.Annotations["k8s.ovn.org/node-subnets"] = "{\"default\":\"10.128.0.0/23\"}"
+					node.Annotations["test.medik8s.io/restored"] = "true"
+				}, false)
+			})
 
-				verifyEvent("Normal", "RemoveNoExecuteTaint", "Remediation process - remove NoExecute taint from healthy remediated node")
+			It("should delete the node and re-create it from the backup", func() {
+				node := verifyNodeIsUnschedulable()
 
-				verifyEvent("Normal", "RemoveFinalizer", "Remediation process - remove finalizer from snr")
+				addUnschedulableTaint(node)
+
+				verifyNodeBackupExists()
+
+				verifyTimeHasBeenRebootedExists()
+
+				verifyNoWatchdogFood()
+
+				verifyEvent("Normal", "DeleteNode", "Remediation process - unhealthy node deleted")
+
+				verifyEvent("Normal", "RestoreNode", "Remediation process - unhealthy node re-created from backup")
+
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
+
+				restoredNode := &v1.Node{}
+				Expect(k8sClient.Get(context.Background(), unhealthyNodeNamespacedName, restoredNode)).To(Succeed())
+				Expect(restoredNode.Labels).To(HaveKeyWithValue("kubernetes.io/hostname", shared.UnhealthyNodeName))
+				Expect(restoredNode.Annotations).To(HaveKeyWithValue("test.medik8s.io/restored", "true"))
+				Expect(restoredNode.Annotations).ToNot(HaveKey("k8s.ovn.org/node-subnets"))
+
+				deleteSNR(snr)
 
 				verifySNRDoesNotExists()
+			})
+		})
 
+		Context("RebootOnly strategy", func() {
+			BeforeEach(func() {
+				remediationStrategy = v1alpha1.RebootOnlyRemediationStrategy
+				setNodeStatus("boot-before-fencing", v1.ConditionFalse)
 			})
 
-			It("The snr agent attempts to keep deleting node resources during temporary api-server failure", func() {
-				node := verifyNodeIsUnschedulable()
+			AfterEach(func() {
+				eventuallyUpdateNode(func(node *v1.Node) {
+					node.Status = v1.NodeStatus{}
+				}, true)
+			})
 
-				k8sClient.ShouldSimulatePodDeleteFailure = true
+			It("should reboot the node and keep its workloads in place", func() {
+				node := verifyNodeIsUnschedulable()
 
 				addUnschedulableTaint(node)
 
-				verifyTypeConditions(snr.Name, metav1.ConditionTrue, metav1.ConditionUnknown, "RemediationStarted")
-
 				verifyTimeHasBeenRebootedExists()
 
 				verifyNoWatchdogFood()
 
-				verifyVaNotDeleted(vaName)
+				verifyTypeConditions(snr.Name, metav1.ConditionTrue, metav1.ConditionUnknown, "RemediationStarted")
 
-				// The kube-api calls for VA fail intentionally. In this case, we expect the snr agent to try
-				// to delete node resources again. So LastError is set to the error every time Reconcile()
-				// is triggered. If it becomes another error, it means something unintended happens.
-				verifyLastErrorKeepsApiError()
+				By("Simulate the node coming back after the reboot")
+				setNodeStatus("boot-after-fencing", v1.ConditionTrue)
 
-				k8sClient.ShouldSimulatePodDeleteFailure = false
+				verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionTrue, "RemediationFinishedSuccessfully")
 
-				verifySelfNodeRemediationPodDoesntExist()
+				verifyEvent("Normal", "NodeRecovered", "Remediation process - node is Ready after it was rebooted")
 
-				deleteVolumeAttachment(vaName, true)
+				verifyTimelinePhases("Fencing-Started", "Pre-Reboot-Completed", "Reboot-Completed", "Fencing-Completed")
+
+				verifySelfNodeRemediationPodExist()
+
+				verifyVaNotDeleted(vaName)
 
 				deleteSNR(snr)
 
+				verifyNodeIsSchedulable()
+
 				removeUnschedulableTaint()
 
 				verifySNRDoesNotExists()
-
 			})
-			When("Node isn't found", func() {
+
+			When("the node isn't Ready in time after it was rebooted", func() {
 				BeforeEach(func() {
-					snr.Name = "non-existing-node"
+					snr.Spec.RecoveryVerificationTimeout = &metav1.Duration{Duration: 5 * time.Second}
 				})
 
-				It("remediation should stop and update conditions", func() {
-					verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionFalse, "RemediationFinishedNodeNotFound")
+				It("should fail the remediation", func() {
+					node := verifyNodeIsUnschedulable()
 
-					verifyEvent("Normal", "RemediationStopped", "couldn't find node matching remediation")
+					addUnschedulableTaint(node)
+
+					verifyTimeHasBeenRebootedExists()
+
+					verifyTypeConditions(snr.Name, metav1.ConditionFalse, metav1.ConditionFalse, "RemediationRecoveryTimedOut")
+
+					verifyNodeRecoveredCondition(metav1.ConditionFalse)
+
+					verifyTimelinePhases("Fencing-Started", "Pre-Reboot-Completed", "Reboot-Completed")
+
+					deleteSNR(snr)
+
+					verifyNodeIsSchedulable()
+
+					removeUnschedulableTaint()
+
+					verifySNRDoesNotExists()
 				})
 			})
 		})
@@ -402,6 +1418,31 @@ func verifyVaNotDeleted(vaName string) {
 	}, 5*time.Second, 250*time.Millisecond).Should(BeFalse())
 }
 
+func verifyVaDeleted(vaName string) {
+	By(fmt.Sprintf("Verify that volumeAttachment %s was deleted", vaName))
+	vaKey := client.ObjectKey{
+		Namespace: shared.Namespace,
+		Name:      vaName,
+	}
+
+	EventuallyWithOffset(1, func() bool {
+		va := &storagev1.VolumeAttachment{}
+		err := k8sClient.Get(context.Background(), vaKey, va)
+		return apierrors.IsNotFound(err)
+	}, 10*time.Second, 250*time.Millisecond).Should(BeTrue())
+}
+
+func removeVaFinalizers(vaName string) {
+	EventuallyWithOffset(1, func() error {
+		va := &storagev1.VolumeAttachment{}
+		if err := k8sClient.Get(context.Background(), client.ObjectKey{Name: vaName}, va); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		va.Finalizers = nil
+		return client.IgnoreNotFound(k8sClient.Update(context.Background(), va))
+	}, 5*time.Second, 250*time.Millisecond).Should(Succeed())
+}
+
 func verifyLastErrorKeepsApiError() {
 	By("Verify that LastError in SNR status has been kept kube-api error for VA")
 	snr := &v1alpha1.SelfNodeRemediation{}
@@ -631,6 +1672,21 @@ func createSelfNodeRemediationPod() {
 	ExpectWithOffset(1, k8sClient.Client.Create(context.Background(), pod)).To(Succeed())
 }
 
+// createPodOnUnhealthyNode creates a pod on the unhealthy node, which is deleted at the end of the test if it still exists
+func createPodOnUnhealthyNode(name string, annotations map[string]string) {
+	pod := &v1.Pod{}
+	pod.Name = name
+	pod.Namespace = shared.Namespace
+	pod.Annotations = annotations
+	pod.Spec.NodeName = shared.UnhealthyNodeName
+	pod.Spec.Containers = []v1.Container{{Name: "foo", Image: "foo"}}
+	ExpectWithOffset(1, k8sClient.Client.Create(context.Background(), pod)).To(Succeed())
+	DeferCleanup(func() {
+		err := k8sClient.Client.Delete(context.Background(), pod, client.GracePeriodSeconds(0))
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+	})
+}
+
 func deleteSelfNodeRemediationPod() {
 	pod := &v1.Pod{}
 
@@ -801,6 +1857,212 @@ func verifyNodesAreEqual(expected *v1.Node, actual *v1.Node) {
 	Expect(reflect.DeepEqual(expected.Labels, actual.Labels)).To(BeTrue())
 }
 
+func verifyRemediationStrategyInStatus(expectedStrategy v1alpha1.RemediationStrategyType) {
+	By("Verify that the used remediation strategy was recorded in the SNR status")
+	snr := &v1alpha1.SelfNodeRemediation{}
+	EventuallyWithOffset(1, func() (v1alpha1.RemediationStrategyType, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr)
+		return snr.Status.RemediationStrategy, err
+	}, 5*time.Second, 250*time.Millisecond).Should(Equal(expectedStrategy))
+}
+
+func verifyNodeBackupExists() {
+	By("Verify that the node was backed up in the SNR status")
+	snr := &v1alpha1.SelfNodeRemediation{}
+	EventuallyWithOffset(1, func() (*v1alpha1.NodeBackup, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr)
+		return snr.Status.NodeBackup, err
+	}, 5*time.Second, 250*time.Millisecond).ShouldNot(BeNil())
+	ExpectWithOffset(1, snr.Status.NodeBackup.Name).To(Equal(shared.UnhealthyNodeName))
+	ExpectWithOffset(1, snr.Status.NodeBackup.Labels).To(HaveKeyWithValue("kubernetes.io/hostname", shared.UnhealthyNodeName))
+}
+
+func verifyTimelinePhases(expectedPhases ...string) {
+	By("Verify that all phase changes were recorded in the SNR timeline")
+	snr := &v1alpha1.SelfNodeRemediation{}
+	EventuallyWithOffset(1, func() ([]string, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		if err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr); err != nil {
+			return nil, err
+		}
+		var phases []string
+		for _, transition := range snr.Status.Timeline {
+			Expect(transition.TransitionTime).ToNot(BeZero())
+			Expect(transition.Actor).ToNot(BeEmpty())
+			Expect(transition.Reason).ToNot(BeEmpty())
+			phases = append(phases, transition.Phase)
+		}
+		return phases, nil
+	}, 5*time.Second, 250*time.Millisecond).Should(Equal(expectedPhases))
+}
+
+func verifyDrainState(expectedState v1alpha1.DrainState) {
+	By(fmt.Sprintf("Verify that the drain state is %s", expectedState))
+	snr := &v1alpha1.SelfNodeRemediation{}
+	EventuallyWithOffset(1, func() (v1alpha1.DrainState, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		if err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr); err != nil {
+			return "", err
+		}
+		if snr.Status.Drain == nil {
+			return "", nil
+		}
+		return snr.Status.Drain.State, nil
+	}, 10*time.Second, 250*time.Millisecond).Should(Equal(expectedState))
+}
+
+func verifyWaitingCondition(expectedStatus metav1.ConditionStatus) {
+	By(fmt.Sprintf("Verify that the Waiting condition is %s", expectedStatus))
+	snr := &v1alpha1.SelfNodeRemediation{}
+	EventuallyWithOffset(1, func() (metav1.ConditionStatus, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		if err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr); err != nil {
+			return "", err
+		}
+		condition := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.WaitingConditionType)
+		if condition == nil {
+			return "", nil
+		}
+		return condition.Status, nil
+	}, 20*time.Second, 250*time.Millisecond).Should(Equal(expectedStatus))
+}
+
+// setRemediationHistory sets the remediation history of the unhealthy node to the given number of recent remediations,
+// and to an additional remediation which is older than the reboot loop time window
+func setRemediationHistory(recentRemediations int) {
+	history := []string{time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)}
+	for i := 1; i <= recentRemediations; i++ {
+		history = append(history, time.Now().Add(-time.Duration(i)*time.Minute).UTC().Format(time.RFC3339))
+	}
+	eventuallyUpdateNode(func(node *v1.Node) {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[v1alpha1.RemediationHistoryAnnotation] = strings.Join(history, ",")
+	}, false)
+}
+
+func setNodeStatus(bootID string, readyStatus v1.ConditionStatus) {
+	eventuallyUpdateNode(func(node *v1.Node) {
+		node.Status.NodeInfo.BootID = bootID
+		node.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: readyStatus}}
+	}, true)
+}
+
+func verifyNodeRecoveredCondition(expectedStatus metav1.ConditionStatus) {
+	By(fmt.Sprintf("Verify that the NodeRecovered condition is %s", expectedStatus))
+	EventuallyWithOffset(1, func() (metav1.ConditionStatus, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		snr := &v1alpha1.SelfNodeRemediation{}
+		if err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr); err != nil {
+			return "", err
+		}
+		condition := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.NodeRecoveredConditionType)
+		if condition == nil {
+			return "", nil
+		}
+		return condition.Status, nil
+	}, 10*time.Second, 250*time.Millisecond).Should(Equal(expectedStatus))
+}
+
+func verifyRebootApprovedCondition(expectedStatus metav1.ConditionStatus) {
+	By(fmt.Sprintf("Verify that the RebootApproved condition is %s", expectedStatus))
+	EventuallyWithOffset(1, func() (metav1.ConditionStatus, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		snr := &v1alpha1.SelfNodeRemediation{}
+		if err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr); err != nil {
+			return "", err
+		}
+		condition := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.RebootApprovedConditionType)
+		if condition == nil {
+			return "", nil
+		}
+		return condition.Status, nil
+	}, 10*time.Second, 250*time.Millisecond).Should(Equal(expectedStatus))
+}
+
+func verifyZoneFailureSuspectedCondition(expectedStatus metav1.ConditionStatus) {
+	By(fmt.Sprintf("Verify that the ZoneFailureSuspected condition is %s", expectedStatus))
+	EventuallyWithOffset(1, func() (metav1.ConditionStatus, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		snr := &v1alpha1.SelfNodeRemediation{}
+		if err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr); err != nil {
+			return "", err
+		}
+		condition := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.ZoneFailureSuspectedConditionType)
+		if condition == nil {
+			return "", nil
+		}
+		return condition.Status, nil
+	}, 20*time.Second, 250*time.Millisecond).Should(Equal(expectedStatus))
+}
+
+func verifyPausedCondition(expectedStatus metav1.ConditionStatus) {
+	By(fmt.Sprintf("Verify that the Paused condition is %s", expectedStatus))
+	EventuallyWithOffset(1, func() (metav1.ConditionStatus, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		snr := &v1alpha1.SelfNodeRemediation{}
+		if err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr); err != nil {
+			return "", err
+		}
+		condition := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.PausedConditionType)
+		if condition == nil {
+			return "", nil
+		}
+		return condition.Status, nil
+	}, 10*time.Second, 250*time.Millisecond).Should(Equal(expectedStatus))
+}
+
+func verifyPostponedCondition(expectedStatus metav1.ConditionStatus) {
+	By(fmt.Sprintf("Verify that the Postponed condition is %s", expectedStatus))
+	snr := &v1alpha1.SelfNodeRemediation{}
+	EventuallyWithOffset(1, func() (metav1.ConditionStatus, error) {
+		snrNamespacedName := client.ObjectKey{Name: shared.UnhealthyNodeName, Namespace: snrNamespace}
+		if err := k8sClient.Client.Get(context.Background(), snrNamespacedName, snr); err != nil {
+			return "", err
+		}
+		condition := meta.FindStatusCondition(snr.Status.Conditions, v1alpha1.PostponedConditionType)
+		if condition == nil {
+			return "", nil
+		}
+		return condition.Status, nil
+	}, 10*time.Second, 250*time.Millisecond).Should(Equal(expectedStatus))
+}
+
+func verifyMetricExists(metricName string) {
+	By(fmt.Sprintf("Verify that metric %s was exported", metricName))
+	EventuallyWithOffset(1, func() (bool, error) {
+		families, err := metrics.Registry.Gather()
+		if err != nil {
+			return false, err
+		}
+		for _, family := range families {
+			if family.GetName() == metricName && len(family.GetMetric()) > 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}, 5*time.Second, 250*time.Millisecond).Should(BeTrue())
+}
+
+// getRemediationsTotal returns the number of finished remediations counted by the remediations_total metric
+func getRemediationsTotal() float64 {
+	families, err := metrics.Registry.Gather()
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	total := 0.0
+	for _, family := range families {
+		if family.GetName() != "self_node_remediation_remediations_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			total += metric.GetCounter().GetValue()
+		}
+	}
+	return total
+}
+
 func verifyEvent(eventType, reason, message string) {
 	expected := fmt.Sprintf("%s %s %s", eventType, reason, message)
 	isEventMatch := false
@@ -18,6 +18,7 @@ package testcontroler
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -76,7 +77,7 @@ var _ = BeforeSuite(func() {
 
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths:     []string{filepath.Join("../../..", "config", "crd", "bases")},
+		CRDDirectoryPaths:     []string{filepath.Join("../../..", "config", "crd", "bases"), filepath.Join("testdata", "crd")},
 		ErrorIfCRDPathMissing: true,
 	}
 
@@ -109,6 +110,7 @@ var _ = BeforeSuite(func() {
 	}
 
 	Expect(k8sClient.Create(context.Background(), nsToCreate)).To(Succeed())
+	Expect(os.Setenv("DEPLOYMENT_NAMESPACE", shared.Namespace)).To(Succeed())
 	dummyDog = watchdog.NewFake(true)
 	err = k8sManager.Add(dummyDog)
 	Expect(err).ToNot(HaveOccurred())
@@ -133,7 +135,7 @@ var _ = BeforeSuite(func() {
 	Expect(k8sClient.Create(context.Background(), peerNode)).To(Succeed(), "failed to create peer node")
 
 	peerApiServerTimeout := 5 * time.Second
-	peers := peers.New(shared.UnhealthyNodeName, shared.PeerUpdateInterval, k8sClient, ctrl.Log.WithName("peers"), peerApiServerTimeout)
+	peers := peers.New(shared.UnhealthyNodeName, shared.PeerUpdateInterval, k8sClient, ctrl.Log.WithName("peers"), peerApiServerTimeout, "")
 	err = k8sManager.Add(peers)
 	Expect(err).ToNot(HaveOccurred())
 
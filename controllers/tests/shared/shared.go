@@ -6,6 +6,9 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -23,7 +26,9 @@ type K8sClientWrapper struct {
 	Reader                         client.Reader
 	ShouldSimulateFailure          bool
 	ShouldSimulatePodDeleteFailure bool
-	SimulatedFailureMessage        string
+	// ShouldSimulateForbiddenCreate denies creating unstructured objects, like missing RBAC permissions would do
+	ShouldSimulateForbiddenCreate bool
+	SimulatedFailureMessage       string
 }
 
 type MockCalculator struct {
@@ -42,6 +47,14 @@ func (kcw *K8sClientWrapper) List(ctx context.Context, list client.ObjectList, o
 	return kcw.Client.List(ctx, list, opts...)
 }
 
+func (kcw *K8sClientWrapper) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if u, ok := obj.(*unstructured.Unstructured); ok && kcw.ShouldSimulateForbiddenCreate {
+		gvk := u.GroupVersionKind()
+		return apiErrors.NewForbidden(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, u.GetName(), errors.New("simulation of missing permissions"))
+	}
+	return kcw.Client.Create(ctx, obj, opts...)
+}
+
 func (m *MockCalculator) GetTimeToAssumeNodeRebooted() time.Duration {
 	return m.MockTimeToAssumeNodeRebooted
 }
@@ -276,6 +276,11 @@ func initSelfNodeRemediationAgent(mgr manager.Manager) {
 		wasWatchdogInitiated = true
 	}
 
+	// needed for resolving the Automatic remediation strategy
+	if err := utils.InitOutOfServiceTaintSupportedFlag(mgr.GetConfig()); err != nil {
+		setupLog.Error(err, "unable to verify out of service taint support. out of service taint isn't supported")
+	}
+
 	if err = utils.UpdateNodeWithIsRebootCapableAnnotation(wasWatchdogInitiated, myNodeName, mgr); err != nil {
 		setupLog.Error(err, "failed to update node's annotation", "annotation", utils.IsRebootCapableAnnotation)
 		os.Exit(1)
@@ -284,8 +289,9 @@ func initSelfNodeRemediationAgent(mgr manager.Manager) {
 	// TODO make the interval configurable
 	peerUpdateInterval := getDurEnvVarOrDie("PEER_UPDATE_INTERVAL")
 	peerApiServerTimeout := getDurEnvVarOrDie("PEER_API_SERVER_TIMEOUT")
+	peerRackLabel := os.Getenv("PEER_RACK_LABEL")
 
-	myPeers := peers.New(myNodeName, peerUpdateInterval, mgr.GetClient(), ctrl.Log.WithName("peers"), peerApiServerTimeout)
+	myPeers := peers.New(myNodeName, peerUpdateInterval, mgr.GetClient(), ctrl.Log.WithName("peers"), peerApiServerTimeout, peerRackLabel)
 	if err = mgr.Add(myPeers); err != nil {
 		setupLog.Error(err, "failed to add peers to the manager")
 		os.Exit(1)
@@ -297,6 +303,9 @@ func initSelfNodeRemediationAgent(mgr manager.Manager) {
 	apiServerTimeout := getDurEnvVarOrDie("API_SERVER_TIMEOUT")       //timeout for each api-connectivity check
 	peerDialTimeout := getDurEnvVarOrDie("PEER_DIAL_TIMEOUT")         //timeout for establishing connection to peer
 	peerRequestTimeout := getDurEnvVarOrDie("PEER_REQUEST_TIMEOUT")   //timeout for each peer request
+	peerAddressPreference := selfnoderemediationv1alpha1.PeerAddressPreference(os.Getenv("PEER_ADDRESS_PREFERENCE"))
+	peerQuorumPolicy := selfnoderemediationv1alpha1.PeerQuorumPolicyType(os.Getenv("PEER_QUORUM_POLICY"))
+	peerQuorumMinAgreeingPeers := getIntEnvVarOrDie("PEER_QUORUM_MIN_AGREEING_PEERS")
 	timeToAssumeNodeRebootedInSeconds := getIntEnvVarOrDie("TIME_TO_ASSUME_NODE_REBOOTED")
 	peerHealthDefaultPort := getIntEnvVarOrDie("HOST_PORT")
 
@@ -313,22 +322,26 @@ func initSelfNodeRemediationAgent(mgr manager.Manager) {
 	certReader := certificates.NewSecretCertStorage(mgr.GetClient(), ctrl.Log.WithName("SecretCertStorage"), ns)
 
 	apiConnectivityCheckConfig := &apicheck.ApiConnectivityCheckConfig{
-		Log:                       ctrl.Log.WithName("api-check"),
-		MyNodeName:                myNodeName,
-		CheckInterval:             apiCheckInterval,
-		MaxErrorsThreshold:        maxErrorThreshold,
-		Peers:                     myPeers,
-		Rebooter:                  rebooter,
-		Cfg:                       mgr.GetConfig(),
-		CertReader:                certReader,
-		ApiServerTimeout:          apiServerTimeout,
-		PeerDialTimeout:           peerDialTimeout,
-		PeerRequestTimeout:        peerRequestTimeout,
-		PeerHealthPort:            peerHealthDefaultPort,
-		MaxTimeForNoPeersResponse: reboot.MaxTimeForNoPeersResponse,
-	}
-
-	controlPlaneManager := controlplane.NewManager(myNodeName, mgr.GetClient())
+		Log:                        ctrl.Log.WithName("api-check"),
+		MyNodeName:                 myNodeName,
+		CheckInterval:              apiCheckInterval,
+		MaxErrorsThreshold:         maxErrorThreshold,
+		Peers:                      myPeers,
+		Rebooter:                   rebooter,
+		Client:                     mgr.GetClient(),
+		Cfg:                        mgr.GetConfig(),
+		CertReader:                 certReader,
+		ApiServerTimeout:           apiServerTimeout,
+		PeerDialTimeout:            peerDialTimeout,
+		PeerRequestTimeout:         peerRequestTimeout,
+		PeerAddressPreference:      peerAddressPreference,
+		PeerQuorumPolicy:           peerQuorumPolicy,
+		PeerQuorumMinAgreeingPeers: peerQuorumMinAgreeingPeers,
+		PeerHealthPort:             peerHealthDefaultPort,
+		MaxTimeForNoPeersResponse:  reboot.MaxTimeForNoPeersResponse,
+	}
+
+	controlPlaneManager := controlplane.NewManager(myNodeName, mgr.GetClient(), mgr.GetConfig(), apiCheckInterval)
 
 	if err = mgr.Add(controlPlaneManager); err != nil {
 		setupLog.Error(err, "failed to add controlPlane remediation manager to setup manager")
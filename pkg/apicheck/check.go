@@ -3,7 +3,11 @@ package apicheck
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,16 +16,20 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	selfNodeRemediation "github.com/medik8s/self-node-remediation/api"
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
 	"github.com/medik8s/self-node-remediation/pkg/certificates"
 	"github.com/medik8s/self-node-remediation/pkg/controlplane"
 	"github.com/medik8s/self-node-remediation/pkg/peerhealth"
 	"github.com/medik8s/self-node-remediation/pkg/peers"
 	"github.com/medik8s/self-node-remediation/pkg/reboot"
+	"github.com/medik8s/self-node-remediation/pkg/utils"
 )
 
 type ApiConnectivityCheck struct {
@@ -32,22 +40,32 @@ type ApiConnectivityCheck struct {
 	clientCreds            credentials.TransportCredentials
 	mutex                  sync.Mutex
 	controlPlaneManager    *controlplane.Manager
+	// isPaused is the last seen paused value of the config, since it can't be read when the api server isn't reachable
+	isPaused bool
+	// healthChecksSpec is the last seen local health checks config, from which the healthChecks were created
+	healthChecksSpec   *v1alpha1.LocalHealthChecks
+	healthChecks       []HealthCheck
+	healthCheckTimeout time.Duration
 }
 
 type ApiConnectivityCheckConfig struct {
-	Log                       logr.Logger
-	MyNodeName                string
-	CheckInterval             time.Duration
-	MaxErrorsThreshold        int
-	Peers                     *peers.Peers
-	Rebooter                  reboot.Rebooter
-	Cfg                       *rest.Config
-	CertReader                certificates.CertStorageReader
-	ApiServerTimeout          time.Duration
-	PeerDialTimeout           time.Duration
-	PeerRequestTimeout        time.Duration
-	PeerHealthPort            int
-	MaxTimeForNoPeersResponse time.Duration
+	Log                        logr.Logger
+	MyNodeName                 string
+	CheckInterval              time.Duration
+	MaxErrorsThreshold         int
+	Peers                      *peers.Peers
+	Rebooter                   reboot.Rebooter
+	Client                     client.Reader
+	Cfg                        *rest.Config
+	CertReader                 certificates.CertStorageReader
+	ApiServerTimeout           time.Duration
+	PeerDialTimeout            time.Duration
+	PeerRequestTimeout         time.Duration
+	PeerAddressPreference      v1alpha1.PeerAddressPreference
+	PeerQuorumPolicy           v1alpha1.PeerQuorumPolicyType
+	PeerQuorumMinAgreeingPeers int
+	PeerHealthPort             int
+	MaxTimeForNoPeersResponse  time.Duration
 }
 
 func New(config *ApiConnectivityCheckConfig, controlPlaneManager *controlplane.Manager) *ApiConnectivityCheck {
@@ -56,6 +74,7 @@ func New(config *ApiConnectivityCheckConfig, controlPlaneManager *controlplane.M
 		mutex:                  sync.Mutex{},
 		controlPlaneManager:    controlPlaneManager,
 		timeOfLastPeerResponse: time.Now(),
+		healthCheckTimeout:     defaultHealthCheckTimeout,
 	}
 }
 
@@ -83,21 +102,25 @@ func (c *ApiConnectivityCheck) Start(ctx context.Context) error {
 				failure = fmt.Sprintf("api server readyz endpoint status code: %v", statusCode)
 			}
 		}
+		if failure == "" {
+			// the local health checks are configured in the config, which can only be read when the api server is reachable
+			c.updateConfigState(ctx)
+			failure = c.runHealthChecks(ctx)
+		}
+		if failure == "" && c.isWorkerKubeletDown() {
+			failure = "kubelet of the worker node is down"
+		}
 		if failure != "" {
-			c.config.Log.Error(fmt.Errorf(failure), "failed to check api server")
+			c.config.Log.Error(fmt.Errorf(failure), "failed to check node health")
 			if isHealthy := c.isConsideredHealthy(); !isHealthy {
-				// we have a problem on this node
-				c.config.Log.Error(err, "we are unhealthy, triggering a reboot")
-				if err := c.config.Rebooter.Reboot(); err != nil {
-					c.config.Log.Error(err, "failed to trigger reboot")
-				}
+				c.rebootIfNotPaused()
 			} else {
 				c.config.Log.Error(err, "peers did not confirm that we are unhealthy, ignoring error")
 			}
 			return
 		}
 
-		// reset error count after a successful API call
+		// reset error count after a successful API call and health checks
 		c.errorCount = 0
 
 	}, c.config.CheckInterval)
@@ -108,19 +131,101 @@ func (c *ApiConnectivityCheck) Start(ctx context.Context) error {
 	return nil
 }
 
+// rebootIfNotPaused triggers a reboot of this unhealthy node, unless remediation is paused by the config
+func (c *ApiConnectivityCheck) rebootIfNotPaused() {
+	if c.isPaused {
+		c.config.Log.Info("we are unhealthy, but remediation is paused by the config, skipping reboot")
+		return
+	}
+	// we have a problem on this node
+	c.config.Log.Info("we are unhealthy, triggering a reboot")
+	if err := c.config.Rebooter.Reboot(); err != nil {
+		c.config.Log.Error(err, "failed to trigger reboot")
+	}
+}
+
+// updateConfigState updates the paused value and the local health checks of the config. On errors the last seen values are kept
+func (c *ApiConnectivityCheck) updateConfigState(ctx context.Context) {
+	if c.config.Client == nil {
+		return
+	}
+
+	ns, err := utils.GetDeploymentNamespace()
+	if err != nil {
+		c.config.Log.Error(err, "failed to get the deployment namespace")
+		return
+	}
+
+	readerCtx, cancel := context.WithTimeout(ctx, c.config.ApiServerTimeout)
+	defer cancel()
+
+	config := &v1alpha1.SelfNodeRemediationConfig{}
+	if err = c.config.Client.Get(readerCtx, client.ObjectKey{Name: v1alpha1.ConfigCRName, Namespace: ns}, config); err != nil {
+		if !apierrors.IsNotFound(err) {
+			c.config.Log.Error(err, "failed to get SelfNodeRemediationConfig, keeping the last seen values", "paused", c.isPaused)
+			return
+		}
+		// without a config nothing is paused and no health checks are configured
+		config = &v1alpha1.SelfNodeRemediationConfig{}
+	}
+
+	c.updatePausedState(config.Spec.Paused)
+	c.updateHealthChecks(config.Spec.LocalHealthChecks)
+}
+
+func (c *ApiConnectivityCheck) updatePausedState(isPaused bool) {
+	if isPaused != c.isPaused {
+		c.config.Log.Info("remediation paused value changed", "paused", isPaused)
+		c.isPaused = isPaused
+	}
+}
+
+// updateHealthChecks recreates the local health checks when their config changed
+func (c *ApiConnectivityCheck) updateHealthChecks(spec *v1alpha1.LocalHealthChecks) {
+	if equality.Semantic.DeepEqual(spec, c.healthChecksSpec) {
+		return
+	}
+	c.config.Log.Info("local health checks config changed", "local health checks", spec)
+	c.healthChecksSpec = spec.DeepCopy()
+	c.healthChecks = newHealthChecks(spec, c.config)
+	c.healthCheckTimeout = getHealthCheckTimeout(spec)
+}
+
+// runHealthChecks runs the local health checks, and returns the failures of the failed ones
+func (c *ApiConnectivityCheck) runHealthChecks(ctx context.Context) string {
+	var failures []string
+	for _, check := range c.healthChecks {
+		checkCtx, cancel := context.WithTimeout(ctx, c.healthCheckTimeout)
+		if err := check.Check(checkCtx); err != nil {
+			failures = append(failures, fmt.Sprintf("%s health check failed: %v", check.Name(), err))
+		}
+		cancel()
+	}
+	return strings.Join(failures, ", ")
+}
+
 // isConsideredHealthy keeps track of the number of errors reported, and when a certain amount of error occur within a certain
 // time, ask peers if this node is healthy. Returns if the node is considered to be healthy or not.
 func (c *ApiConnectivityCheck) isConsideredHealthy() bool {
 	workerPeersResponse := c.getWorkerPeersResponse()
 	isWorkerNode := c.controlPlaneManager == nil || !c.controlPlaneManager.IsControlPlane()
 	if isWorkerNode {
-		return workerPeersResponse.IsHealthy
+		if c.controlPlaneManager == nil {
+			return workerPeersResponse.IsHealthy
+		}
+		return c.controlPlaneManager.IsWorkerHealthy(workerPeersResponse)
 	} else {
 		return c.controlPlaneManager.IsControlPlaneHealthy(workerPeersResponse, c.canOtherControlPlanesBeReached())
 	}
 
 }
 
+// isWorkerKubeletDown returns whether this is a worker node, whose kubelet was found to be down by the control plane
+// manager. Its peers might still be able to access the api server, and report it as healthy
+func (c *ApiConnectivityCheck) isWorkerKubeletDown() bool {
+	return c.controlPlaneManager != nil && !c.controlPlaneManager.IsControlPlane() && c.controlPlaneManager.IsKubeletDown()
+}
+
 func (c *ApiConnectivityCheck) getWorkerPeersResponse() peers.Response {
 	c.errorCount++
 	if c.errorCount < c.config.MaxErrorsThreshold {
@@ -136,7 +241,8 @@ func (c *ApiConnectivityCheck) getWorkerPeersResponse() peers.Response {
 		return peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseNoPeersWereFound}
 	}
 
-	apiErrorsResponsesSum := 0
+	votes := peerVotes{}
+	quorum := c.getPeerQuorum()
 	nrAllNodes := len(nodesToAsk)
 	// nodesToAsk is being reduced in every iteration, iterate until no nodes left to ask
 	for i := 0; len(nodesToAsk) > 0; i++ {
@@ -157,27 +263,27 @@ func (c *ApiConnectivityCheck) getWorkerPeersResponse() peers.Response {
 		}
 
 		chosenNodesAddresses := c.popNodes(&nodesToAsk, nodesBatchCount)
-		healthyResponses, unhealthyResponses, apiErrorsResponses, _ := c.getHealthStatusFromPeers(chosenNodesAddresses)
+		healthyResponses, unhealthyResponses, apiErrorsResponses, noResponses := c.getHealthStatusFromPeers(chosenNodesAddresses)
 		if healthyResponses+unhealthyResponses+apiErrorsResponses > 0 {
 			c.timeOfLastPeerResponse = time.Now()
 		}
-
-		if healthyResponses > 0 {
-			c.config.Log.Info("Peer told me I'm healthy.")
-			c.errorCount = 0
-			return peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseCRNotFound}
-		}
-
-		if unhealthyResponses > 0 {
-			c.config.Log.Info("Peer told me I'm unhealthy!")
+		votes.add(healthyResponses, unhealthyResponses, apiErrorsResponses, noResponses)
+		c.config.Log.Info("Peers votes", append(votes.keysAndValues(), "quorum policy", quorum.policy)...)
+
+		if isHealthy, isDecided := quorum.getVerdict(votes, nrAllNodes); isDecided {
+			if isHealthy {
+				c.config.Log.Info("Peers told me I'm healthy.")
+				c.errorCount = 0
+				return peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseCRNotFound}
+			}
+			c.config.Log.Info("Peers told me I'm unhealthy!")
 			return peers.Response{IsHealthy: false, Reason: peers.UnHealthyBecausePeersResponse}
 		}
 
 		if apiErrorsResponses > 0 {
 			c.config.Log.Info("Peer can't access the api-server")
-			apiErrorsResponsesSum += apiErrorsResponses
 			//todo consider using [m|n]hc.spec.maxUnhealthy instead of 50%
-			if apiErrorsResponsesSum > nrAllNodes/2 { //already reached more than 50% of the nodes and all of them returned api error
+			if votes.apiErrors > nrAllNodes/2 { //already reached more than 50% of the nodes and all of them returned api error
 				//assuming this is a control plane failure as others can't access api-server as well
 				c.config.Log.Info("More than 50% of the nodes couldn't access the api-server, assuming this is a control plane failure")
 				return peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseMostPeersCantAccessAPIServer}
@@ -187,6 +293,11 @@ func (c *ApiConnectivityCheck) getWorkerPeersResponse() peers.Response {
 	}
 
 	//we asked all peers
+	if votes.healthy+votes.unhealthy > 0 {
+		c.config.Log.Info("Peers didn't reach a quorum on whether I'm healthy, ignoring error for now", append(votes.keysAndValues(), "quorum policy", quorum.policy)...)
+		return peers.Response{IsHealthy: true, Reason: peers.HealthyBecausePeersQuorumNotReached}
+	}
+
 	now := time.Now()
 	if now.After(c.timeOfLastPeerResponse.Add(c.config.MaxTimeForNoPeersResponse)) {
 		c.config.Log.Error(fmt.Errorf("failed health check"), "Failed to get health status peers. Assuming unhealthy")
@@ -207,53 +318,100 @@ func (c *ApiConnectivityCheck) canOtherControlPlanesBeReached() bool {
 	}
 
 	chosenNodesAddresses := c.popNodes(&nodesToAsk, numOfControlPlanePeers)
-	healthyResponses, unhealthyResponses, apiErrorsResponses, _ := c.getHealthStatusFromPeers(chosenNodesAddresses)
+	votes := peerVotes{}
+	votes.add(c.getHealthStatusFromPeers(chosenNodesAddresses))
+
+	// Any response is an indication of communication with a peer, the quorum defines how many peers need to respond
+	quorum := c.getPeerQuorum()
+	isReached := quorum.isReached(votes, numOfControlPlanePeers)
+	c.config.Log.Info("Control plane peers votes", append(votes.keysAndValues(), "quorum policy", quorum.policy, "reached", isReached)...)
+	return isReached
+}
 
-	// Any response is an indication of communication with a peer
-	return (healthyResponses + unhealthyResponses + apiErrorsResponses) > 0
+func (c *ApiConnectivityCheck) getPeerQuorum() peerQuorum {
+	return peerQuorum{policy: c.config.PeerQuorumPolicy, minAgreeingPeers: c.config.PeerQuorumMinAgreeingPeers}
 }
 
-func (c *ApiConnectivityCheck) popNodes(nodes *[][]v1.NodeAddress, count int) []string {
-	nrOfNodes := len(*nodes)
-	if nrOfNodes == 0 {
-		return []string{}
+// popNodes pops nodes from the given list until it found the given count of nodes with usable addresses, and returns
+// the usable addresses of each found node, in the order in which they should be tried
+func (c *ApiConnectivityCheck) popNodes(nodes *[][]v1.NodeAddress, count int) [][]string {
+	addresses := make([][]string, 0, count)
+	for len(*nodes) > 0 && len(addresses) < count {
+		nodeAddresses := getUsableAddresses((*nodes)[0], c.config.PeerAddressPreference)
+		*nodes = (*nodes)[1:] //remove popped node from the list
+		if len(nodeAddresses) == 0 {
+			c.config.Log.Info("ignoring node without usable IP address")
+			continue
+		}
+		addresses = append(addresses, nodeAddresses)
 	}
 
-	if count > nrOfNodes {
-		count = nrOfNodes
-	}
+	return addresses
+}
 
-	//todo maybe we should pick nodes randomly rather than relying on the order returned from api-server
-	addresses := make([]string, count)
-	for i := 0; i < count; i++ {
-		nodeAddresses := (*nodes)[i]
-		if len(nodeAddresses) == 0 || nodeAddresses[0].Address == "" {
-			c.config.Log.Info("ignoring node without IP address")
+// getUsableAddresses returns the InternalIP and ExternalIP addresses of a node, ordered by the given preference
+func getUsableAddresses(nodeAddresses []v1.NodeAddress, preference v1alpha1.PeerAddressPreference) []string {
+	var addresses []v1.NodeAddress
+	seen := map[string]bool{}
+	for _, nodeAddress := range nodeAddresses {
+		if nodeAddress.Type != v1.NodeInternalIP && nodeAddress.Type != v1.NodeExternalIP {
 			continue
 		}
-		addresses[i] = nodeAddresses[0].Address //todo node might have multiple addresses or none
+		if net.ParseIP(nodeAddress.Address) == nil || seen[nodeAddress.Address] {
+			continue
+		}
+		seen[nodeAddress.Address] = true
+		addresses = append(addresses, nodeAddress)
 	}
 
-	*nodes = (*nodes)[count:] //remove popped nodes from the list
+	sort.SliceStable(addresses, func(i, j int) bool {
+		return getAddressRank(addresses[i], preference) < getAddressRank(addresses[j], preference)
+	})
 
-	return addresses
+	usableAddresses := make([]string, len(addresses))
+	for i, address := range addresses {
+		usableAddresses[i] = address.Address
+	}
+	return usableAddresses
+}
+
+// getAddressRank returns a lower rank for addresses which should be tried first.
+// Addresses which match the preference come first, and the remaining order is internal before external, and IPv4 before IPv6
+func getAddressRank(address v1.NodeAddress, preference v1alpha1.PeerAddressPreference) int {
+	isExternal := address.Type == v1.NodeExternalIP
+	isIPv6 := net.ParseIP(address.Address).To4() == nil
+	switch preference {
+	case v1alpha1.ExternalIPPeerAddressPreference:
+		return rankOf(!isExternal)*2 + rankOf(isIPv6)
+	case v1alpha1.IPv6PeerAddressPreference:
+		return rankOf(!isIPv6)*2 + rankOf(isExternal)
+	default:
+		return rankOf(isExternal)*2 + rankOf(isIPv6)
+	}
 }
 
-func (c *ApiConnectivityCheck) getHealthStatusFromPeers(addresses []string) (int, int, int, int) {
+func rankOf(isLater bool) int {
+	if isLater {
+		return 1
+	}
+	return 0
+}
+
+func (c *ApiConnectivityCheck) getHealthStatusFromPeers(addresses [][]string) (int, int, int, int) {
 	nrAddresses := len(addresses)
 	responsesChan := make(chan selfNodeRemediation.HealthCheckResponseCode, nrAddresses)
 
-	for _, address := range addresses {
-		go c.getHealthStatusFromPeer(address, responsesChan)
+	for _, peerAddresses := range addresses {
+		go c.getHealthStatusFromPeer(peerAddresses, responsesChan)
 	}
 
 	return c.sumPeersResponses(nrAddresses, responsesChan)
 }
 
-// getHealthStatusFromPeer issues a GET request to the specified IP and returns the result from the peer into the given channel
-func (c *ApiConnectivityCheck) getHealthStatusFromPeer(endpointIp string, results chan<- selfNodeRemediation.HealthCheckResponseCode) {
+// getHealthStatusFromPeer issues a GET request to the first reachable of the specified IPs of a peer and returns the result from the peer into the given channel
+func (c *ApiConnectivityCheck) getHealthStatusFromPeer(peerAddresses []string, results chan<- selfNodeRemediation.HealthCheckResponseCode) {
 
-	logger := c.config.Log.WithValues("IP", endpointIp)
+	logger := c.config.Log.WithValues("IPs", peerAddresses)
 	logger.Info("getting health status from peer")
 
 	if err := c.initClientCreds(); err != nil {
@@ -262,14 +420,14 @@ func (c *ApiConnectivityCheck) getHealthStatusFromPeer(endpointIp string, result
 		return
 	}
 
-	// TODO does this work with IPv6?
-	phClient, err := peerhealth.NewClient(fmt.Sprintf("%v:%v", endpointIp, c.config.PeerHealthPort), c.config.PeerDialTimeout, c.config.Log.WithName("peerhealth client"), c.clientCreds)
+	phClient, endpointIp, err := c.dialPeer(peerAddresses, logger)
 	if err != nil {
 		logger.Error(err, "failed to init grpc client")
 		results <- selfNodeRemediation.RequestFailed
 		return
 	}
 	defer phClient.Close()
+	logger = c.config.Log.WithValues("IP", endpointIp)
 
 	ctx, cancel := context.WithTimeout(context.Background(), c.config.PeerRequestTimeout)
 	defer cancel()
@@ -289,6 +447,22 @@ func (c *ApiConnectivityCheck) getHealthStatusFromPeer(endpointIp string, result
 	return
 }
 
+// dialPeer connects to the first reachable of the given addresses of a peer, and returns the client and the address.
+// The addresses share the peer dial timeout, so that failing over between them doesn't take longer than dialing a single address
+func (c *ApiConnectivityCheck) dialPeer(peerAddresses []string, logger logr.Logger) (*peerhealth.Client, string, error) {
+	dialTimeout := c.config.PeerDialTimeout / time.Duration(len(peerAddresses))
+	var err error
+	for _, address := range peerAddresses {
+		var phClient *peerhealth.Client
+		phClient, err = peerhealth.NewClient(net.JoinHostPort(address, strconv.Itoa(c.config.PeerHealthPort)), dialTimeout, c.config.Log.WithName("peerhealth client"), c.clientCreds)
+		if err == nil {
+			return phClient, address, nil
+		}
+		logger.Info("failed to connect to peer address, trying the next one", "IP", address)
+	}
+	return nil, "", err
+}
+
 func (c *ApiConnectivityCheck) initClientCreds() error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -0,0 +1,100 @@
+package apicheck
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+func TestGetUsableAddresses(t *testing.T) {
+	nodeAddresses := []v1.NodeAddress{
+		{Type: v1.NodeHostName, Address: "worker-1"},
+		{Type: v1.NodeExternalIP, Address: "2001:db8::2"},
+		{Type: v1.NodeExternalIP, Address: "203.0.113.1"},
+		{Type: v1.NodeInternalIP, Address: "fd00::1"},
+		{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+		{Type: v1.NodeInternalIP, Address: "10.0.0.2"},
+		{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+		{Type: v1.NodeInternalDNS, Address: "worker-1.internal"},
+	}
+	tests := []struct {
+		name       string
+		preference v1alpha1.PeerAddressPreference
+		want       []string
+	}{
+		{name: "internalIP", preference: v1alpha1.InternalIPPeerAddressPreference, want: []string{"10.0.0.1", "10.0.0.2", "fd00::1", "203.0.113.1", "2001:db8::2"}},
+		{name: "externalIP", preference: v1alpha1.ExternalIPPeerAddressPreference, want: []string{"203.0.113.1", "2001:db8::2", "10.0.0.1", "10.0.0.2", "fd00::1"}},
+		{name: "ipv6", preference: v1alpha1.IPv6PeerAddressPreference, want: []string{"fd00::1", "2001:db8::2", "10.0.0.1", "10.0.0.2", "203.0.113.1"}},
+		{name: "unset", preference: "", want: []string{"10.0.0.1", "10.0.0.2", "fd00::1", "203.0.113.1", "2001:db8::2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getUsableAddresses(nodeAddresses, tt.preference); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getUsableAddresses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopNodes(t *testing.T) {
+	c := New(&ApiConnectivityCheckConfig{Log: logr.Discard()}, nil)
+	nodes := [][]v1.NodeAddress{
+		{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}},
+		{},
+		{{Type: v1.NodeHostName, Address: "worker-3"}},
+		{{Type: v1.NodeInternalIP, Address: "10.0.0.4"}, {Type: v1.NodeInternalIP, Address: "fd00::4"}},
+		{{Type: v1.NodeInternalIP, Address: "10.0.0.5"}},
+	}
+
+	// nodes without usable addresses don't use up the count
+	if got, want := c.popNodes(&nodes, 2), [][]string{{"10.0.0.1"}, {"10.0.0.4", "fd00::4"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("popNodes() = %v, want %v", got, want)
+	}
+	if len(nodes) != 1 {
+		t.Errorf("popNodes() left %d nodes, want 1", len(nodes))
+	}
+
+	if got, want := c.popNodes(&nodes, 2), [][]string{{"10.0.0.5"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("popNodes() = %v, want %v", got, want)
+	}
+	if got := c.popNodes(&nodes, 2); len(got) != 0 {
+		t.Errorf("popNodes() = %v, want no nodes", got)
+	}
+}
+
+type fakeRebooter struct {
+	reboots int
+}
+
+func (r *fakeRebooter) Reboot() error {
+	r.reboots++
+	return nil
+}
+
+func TestRebootIfNotPaused(t *testing.T) {
+	tests := []struct {
+		name        string
+		isPaused    bool
+		wantReboots int
+	}{
+		{name: "notPaused", isPaused: false, wantReboots: 1},
+		{name: "paused", isPaused: true, wantReboots: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rebooter := &fakeRebooter{}
+			c := New(&ApiConnectivityCheckConfig{Log: logr.Discard(), Rebooter: rebooter}, nil)
+			c.updatePausedState(tt.isPaused)
+
+			c.rebootIfNotPaused()
+			if rebooter.reboots != tt.wantReboots {
+				t.Errorf("rebootIfNotPaused() rebooted %d times, want %d", rebooter.reboots, tt.wantReboots)
+			}
+		})
+	}
+}
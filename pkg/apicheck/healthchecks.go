@@ -0,0 +1,240 @@
+package apicheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+	"github.com/medik8s/self-node-remediation/pkg/kubelet"
+)
+
+const (
+	// hostRootPath is where the daemonset mounts the root filesystem of the node
+	hostRootPath = "/host"
+	// criVersionMethod is the cheapest call of the CRI runtime service, which still needs the runtime to answer
+	criVersionMethod           = "/runtime.v1.RuntimeService/Version"
+	diskWritabilityFilePattern = ".snr-health-check-*"
+	meminfoPath                = "/proc/meminfo"
+
+	defaultHealthCheckTimeout = 5 * time.Second
+)
+
+// HealthCheck is a check of the node's own health, which runs in addition to the api server connectivity check
+type HealthCheck interface {
+	// Name identifies the check in logs
+	Name() string
+	// Check returns an error when the node isn't healthy
+	Check(ctx context.Context) error
+}
+
+// newHealthChecks creates the health checks which are enabled in the given config
+func newHealthChecks(spec *v1alpha1.LocalHealthChecks, config *ApiConnectivityCheckConfig) []HealthCheck {
+	if spec == nil {
+		return nil
+	}
+
+	var checks []HealthCheck
+	if spec.Kubelet != nil {
+		if checker, err := kubelet.NewHealthChecker(config.MyNodeName, spec.Kubelet.Port, config.Cfg); err != nil {
+			config.Log.Error(err, "failed to create the kubelet health check, skipping it")
+		} else {
+			checks = append(checks, &kubeletHealthCheck{checker})
+		}
+	}
+	if spec.ContainerRuntime != nil {
+		checks = append(checks, &containerRuntimeHealthCheck{socketPath: filepath.Join(hostRootPath, spec.ContainerRuntime.SocketPath)})
+	}
+	if spec.DiskWritability != nil {
+		checks = append(checks, &diskWritabilityHealthCheck{path: filepath.Join(hostRootPath, spec.DiskWritability.Path)})
+	}
+	if spec.MemoryPressure != nil {
+		checks = append(checks, &memoryPressureHealthCheck{meminfoPath: meminfoPath, minAvailablePercentage: spec.MemoryPressure.MinAvailablePercentage})
+	}
+	return checks
+}
+
+// getHealthCheckTimeout returns the timeout of each health check in the given config
+func getHealthCheckTimeout(spec *v1alpha1.LocalHealthChecks) time.Duration {
+	if spec == nil || spec.Timeout == nil {
+		return defaultHealthCheckTimeout
+	}
+	return spec.Timeout.Duration
+}
+
+// kubeletHealthCheck checks the /healthz endpoint of the kubelet
+type kubeletHealthCheck struct {
+	*kubelet.HealthChecker
+}
+
+func (c *kubeletHealthCheck) Name() string {
+	return "kubelet"
+}
+
+// containerRuntimeHealthCheck checks that the container runtime answers a version request on its CRI socket
+type containerRuntimeHealthCheck struct {
+	socketPath string
+}
+
+func (c *containerRuntimeHealthCheck) Name() string {
+	return "container runtime"
+}
+
+func (c *containerRuntimeHealthCheck) Check(ctx context.Context) error {
+	// the dial and the call are cancelled with the context, so unlike file system calls they can't outlive the check
+	conn, err := grpc.DialContext(ctx, "unix://"+c.socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return fmt.Errorf("failed to connect to the CRI socket %s: %v", c.socketPath, err)
+	}
+	defer conn.Close()
+
+	// an empty message is a valid VersionRequest, and the response content doesn't matter
+	var resp []byte
+	if err = conn.Invoke(ctx, criVersionMethod, []byte{}, &resp, grpc.ForceCodec(rawCodec{})); err != nil {
+		return fmt.Errorf("failed to get the container runtime version: %v", err)
+	}
+	return nil
+}
+
+// rawCodec passes messages through as they are, for calling the CRI without depending on its generated code
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return msg, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*msg = data
+	return nil
+}
+
+// Name returns the proto codec name, since the messages are protobuf encoded, and servers reject unknown codecs
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// diskWritabilityHealthCheck checks that a file can be written and synced to a directory
+type diskWritabilityHealthCheck struct {
+	path     string
+	inFlight inFlightGuard
+}
+
+func (c *diskWritabilityHealthCheck) Name() string {
+	return "disk writability"
+}
+
+func (c *diskWritabilityHealthCheck) Check(ctx context.Context) error {
+	return c.inFlight.run(ctx, func() error {
+		file, err := os.CreateTemp(c.path, diskWritabilityFilePattern)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(file.Name())
+
+		if _, err = file.WriteString(time.Now().String()); err == nil {
+			err = file.Sync()
+		}
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	})
+}
+
+// memoryPressureHealthCheck checks that the available memory doesn't drop below a percentage of the total memory
+type memoryPressureHealthCheck struct {
+	meminfoPath            string
+	minAvailablePercentage int
+	inFlight               inFlightGuard
+}
+
+func (c *memoryPressureHealthCheck) Name() string {
+	return "memory pressure"
+}
+
+func (c *memoryPressureHealthCheck) Check(ctx context.Context) error {
+	return c.inFlight.run(ctx, func() error {
+		total, available, err := readMeminfo(c.meminfoPath)
+		if err != nil {
+			return err
+		}
+		if availablePercentage := available * 100 / total; availablePercentage < int64(c.minAvailablePercentage) {
+			return fmt.Errorf("only %d%% of the memory is available, expected at least %d%%", availablePercentage, c.minAvailablePercentage)
+		}
+		return nil
+	})
+}
+
+// readMeminfo returns the total and the available memory in kB
+func readMeminfo(path string) (int64, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	values := map[string]int64{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// e.g. "MemAvailable:   12345678 kB"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		if value, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			values[strings.TrimSuffix(fields[0], ":")] = value
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	total, hasTotal := values["MemTotal"]
+	available, hasAvailable := values["MemAvailable"]
+	if !hasTotal || !hasAvailable || total == 0 {
+		return 0, 0, fmt.Errorf("failed to find the total and available memory in %s", path)
+	}
+	return total, available, nil
+}
+
+// inFlightGuard runs a function in the background and returns when it returns or the context is done, whichever happens
+// first, since file system calls can't be cancelled, and hang e.g. on a broken disk. A function which is still in flight
+// from an earlier call isn't started again, but waited for, so that hanging calls don't pile up.
+// It isn't safe for concurrent use, the health checks run one after the other
+type inFlightGuard struct {
+	// result receives the result of the function in flight, it's nil when no function is in flight
+	result chan error
+}
+
+func (g *inFlightGuard) run(ctx context.Context, f func() error) error {
+	if g.result == nil {
+		result := make(chan error, 1)
+		go func() {
+			result <- f()
+		}()
+		g.result = result
+	}
+	select {
+	case err := <-g.result:
+		g.result = nil
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,85 @@
+package apicheck
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryPressureHealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		meminfo string
+		wantErr bool
+	}{
+		{name: "enoughAvailable", meminfo: "MemTotal:       1000 kB\nMemFree:         10 kB\nMemAvailable:    100 kB\n", wantErr: false},
+		{name: "notEnoughAvailable", meminfo: "MemTotal:       1000 kB\nMemFree:         10 kB\nMemAvailable:     40 kB\n", wantErr: true},
+		{name: "missingAvailable", meminfo: "MemTotal:       1000 kB\nMemFree:         10 kB\n", wantErr: true},
+		{name: "empty", meminfo: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "meminfo")
+			if err := os.WriteFile(path, []byte(tt.meminfo), 0600); err != nil {
+				t.Fatalf("failed to write meminfo: %v", err)
+			}
+			check := &memoryPressureHealthCheck{meminfoPath: path, minAvailablePercentage: 5}
+			if err := check.Check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiskWritabilityHealthCheck(t *testing.T) {
+	dir := t.TempDir()
+	check := &diskWritabilityHealthCheck{path: dir}
+	if err := check.Check(context.Background()); err != nil {
+		t.Errorf("Check() error = %v, expected no error", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Check() left %d files behind", len(entries))
+	}
+
+	check = &diskWritabilityHealthCheck{path: filepath.Join(dir, "missing")}
+	if err := check.Check(context.Background()); err == nil {
+		t.Errorf("Check() expected an error for a missing directory")
+	}
+}
+
+func TestInFlightGuard(t *testing.T) {
+	guard := &inFlightGuard{}
+	release := make(chan struct{})
+	calls := 0
+	hangingFunc := func() error {
+		calls++
+		<-release
+		return errors.New("released")
+	}
+
+	// the function hangs, so every call times out, but it's started only once
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		if err := guard.run(ctx, hangingFunc); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("run() error = %v, expected a deadline exceeded error", err)
+		}
+		cancel()
+	}
+
+	// the next call gets the result of the function in flight
+	close(release)
+	if err := guard.run(context.Background(), hangingFunc); err == nil || err.Error() != "released" {
+		t.Errorf("run() error = %v, expected the result of the function in flight", err)
+	}
+	if calls != 1 {
+		t.Errorf("run() started the function %d times, expected 1", calls)
+	}
+
+	// once the function returned, it's started again
+	if err := guard.run(context.Background(), func() error { return nil }); err != nil {
+		t.Errorf("run() error = %v, expected no error", err)
+	}
+}
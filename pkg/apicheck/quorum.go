@@ -0,0 +1,99 @@
+package apicheck
+
+import (
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+	"github.com/medik8s/self-node-remediation/pkg/metrics"
+)
+
+// peerVotes is the tally of the answers of the asked peers
+type peerVotes struct {
+	healthy    int
+	unhealthy  int
+	apiErrors  int
+	noResponse int
+}
+
+func (v *peerVotes) add(healthy, unhealthy, apiErrors, noResponse int) {
+	v.healthy += healthy
+	v.unhealthy += unhealthy
+	v.apiErrors += apiErrors
+	v.noResponse += noResponse
+
+	metrics.ObservePeerVotes(metrics.PeerVoteHealthy, healthy)
+	metrics.ObservePeerVotes(metrics.PeerVoteUnhealthy, unhealthy)
+	metrics.ObservePeerVotes(metrics.PeerVoteApiError, apiErrors)
+	metrics.ObservePeerVotes(metrics.PeerVoteNoResponse, noResponse)
+}
+
+func (v *peerVotes) responses() int {
+	return v.healthy + v.unhealthy + v.apiErrors
+}
+
+// keysAndValues returns the tally for logging
+func (v *peerVotes) keysAndValues() []interface{} {
+	return []interface{}{"healthy", v.healthy, "unhealthy", v.unhealthy, "api errors", v.apiErrors, "no response", v.noResponse}
+}
+
+// peerQuorum decides whether the peers' votes are enough for acting on them
+type peerQuorum struct {
+	policy           v1alpha1.PeerQuorumPolicyType
+	minAgreeingPeers int
+}
+
+// getVerdict returns whether the peers consider the node to be healthy, and whether a verdict meets the quorum yet.
+// nrPeers is the number of peers which can be asked.
+func (q peerQuorum) getVerdict(votes peerVotes, nrPeers int) (isHealthy bool, isDecided bool) {
+	switch q.policy {
+	case v1alpha1.MajorityPeerQuorumPolicy:
+		// peers which can't reach the api server answered too, so they count against both verdicts
+		responses := votes.responses()
+		if votes.healthy*2 > responses {
+			return true, true
+		}
+		if votes.unhealthy*2 > responses {
+			return false, true
+		}
+	case v1alpha1.MinAgreeingPeerQuorumPolicy:
+		minAgreeing := q.getMinAgreeingPeers(nrPeers)
+		isHealthyMet, isUnhealthyMet := votes.healthy >= minAgreeing, votes.unhealthy >= minAgreeing
+		// when both verdicts met the quorum in the same batch, the one with more votes wins
+		if isHealthyMet && (!isUnhealthyMet || votes.healthy > votes.unhealthy) {
+			return true, true
+		}
+		if isUnhealthyMet && (!isHealthyMet || votes.unhealthy > votes.healthy) {
+			return false, true
+		}
+	default:
+		if votes.healthy > 0 {
+			return true, true
+		}
+		if votes.unhealthy > 0 {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// isReached returns whether enough of the given number of asked peers answered, regardless of their verdict
+func (q peerQuorum) isReached(votes peerVotes, nrPeers int) bool {
+	switch q.policy {
+	case v1alpha1.MajorityPeerQuorumPolicy:
+		return votes.responses()*2 > nrPeers
+	case v1alpha1.MinAgreeingPeerQuorumPolicy:
+		return votes.responses() >= q.getMinAgreeingPeers(nrPeers)
+	default:
+		return votes.responses() > 0
+	}
+}
+
+// getMinAgreeingPeers returns the configured min agreeing peers, but not more than there are peers
+func (q peerQuorum) getMinAgreeingPeers(nrPeers int) int {
+	minAgreeing := q.minAgreeingPeers
+	if minAgreeing > nrPeers {
+		minAgreeing = nrPeers
+	}
+	if minAgreeing < 1 {
+		minAgreeing = 1
+	}
+	return minAgreeing
+}
@@ -0,0 +1,71 @@
+package apicheck
+
+import (
+	"testing"
+
+	"github.com/medik8s/self-node-remediation/api/v1alpha1"
+)
+
+func TestPeerQuorumGetVerdict(t *testing.T) {
+	firstResponse := peerQuorum{policy: v1alpha1.FirstResponsePeerQuorumPolicy}
+	majority := peerQuorum{policy: v1alpha1.MajorityPeerQuorumPolicy}
+	minAgreeing := peerQuorum{policy: v1alpha1.MinAgreeingPeerQuorumPolicy, minAgreeingPeers: 2}
+	tests := []struct {
+		name          string
+		quorum        peerQuorum
+		votes         peerVotes
+		nrPeers       int
+		wantIsHealthy bool
+		wantIsDecided bool
+	}{
+		{name: "firstResponseHealthy", quorum: firstResponse, votes: peerVotes{healthy: 1, unhealthy: 2}, nrPeers: 10, wantIsHealthy: true, wantIsDecided: true},
+		{name: "firstResponseUnhealthy", quorum: firstResponse, votes: peerVotes{unhealthy: 1, apiErrors: 2}, nrPeers: 10, wantIsHealthy: false, wantIsDecided: true},
+		{name: "firstResponseNoVerdict", quorum: firstResponse, votes: peerVotes{apiErrors: 2, noResponse: 1}, nrPeers: 10, wantIsDecided: false},
+		{name: "unsetPolicyIsFirstResponse", quorum: peerQuorum{}, votes: peerVotes{healthy: 1, unhealthy: 2}, nrPeers: 10, wantIsHealthy: true, wantIsDecided: true},
+		{name: "majorityHealthy", quorum: majority, votes: peerVotes{healthy: 2, unhealthy: 1}, nrPeers: 10, wantIsHealthy: true, wantIsDecided: true},
+		{name: "majorityUnhealthy", quorum: majority, votes: peerVotes{healthy: 1, unhealthy: 3, noResponse: 5}, nrPeers: 10, wantIsHealthy: false, wantIsDecided: true},
+		{name: "majorityApiErrorsCount", quorum: majority, votes: peerVotes{healthy: 1, apiErrors: 2}, nrPeers: 10, wantIsDecided: false},
+		{name: "majorityApiErrorsOutnumberUnhealthy", quorum: majority, votes: peerVotes{healthy: 1, unhealthy: 2, apiErrors: 5}, nrPeers: 10, wantIsDecided: false},
+		{name: "majorityUnhealthyDespiteApiError", quorum: majority, votes: peerVotes{unhealthy: 2, apiErrors: 1}, nrPeers: 10, wantIsHealthy: false, wantIsDecided: true},
+		{name: "majorityTie", quorum: majority, votes: peerVotes{healthy: 1, unhealthy: 1}, nrPeers: 10, wantIsDecided: false},
+		{name: "majorityNoVerdict", quorum: majority, votes: peerVotes{noResponse: 3}, nrPeers: 10, wantIsDecided: false},
+		{name: "minAgreeingHealthy", quorum: minAgreeing, votes: peerVotes{healthy: 2, unhealthy: 1}, nrPeers: 10, wantIsHealthy: true, wantIsDecided: true},
+		{name: "minAgreeingUnhealthy", quorum: minAgreeing, votes: peerVotes{unhealthy: 2}, nrPeers: 10, wantIsHealthy: false, wantIsDecided: true},
+		{name: "minAgreeingNotMet", quorum: minAgreeing, votes: peerVotes{healthy: 1, unhealthy: 1}, nrPeers: 10, wantIsDecided: false},
+		{name: "minAgreeingBothMetTie", quorum: minAgreeing, votes: peerVotes{healthy: 2, unhealthy: 2}, nrPeers: 10, wantIsDecided: false},
+		{name: "minAgreeingBothMetUnhealthyWins", quorum: minAgreeing, votes: peerVotes{healthy: 2, unhealthy: 3}, nrPeers: 10, wantIsHealthy: false, wantIsDecided: true},
+		{name: "minAgreeingCappedByPeers", quorum: minAgreeing, votes: peerVotes{unhealthy: 1}, nrPeers: 1, wantIsHealthy: false, wantIsDecided: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isHealthy, isDecided := tt.quorum.getVerdict(tt.votes, tt.nrPeers)
+			if isDecided != tt.wantIsDecided || (isDecided && isHealthy != tt.wantIsHealthy) {
+				t.Errorf("getVerdict() = %v, %v, want %v, %v", isHealthy, isDecided, tt.wantIsHealthy, tt.wantIsDecided)
+			}
+		})
+	}
+}
+
+func TestPeerQuorumIsReached(t *testing.T) {
+	tests := []struct {
+		name    string
+		quorum  peerQuorum
+		votes   peerVotes
+		nrPeers int
+		want    bool
+	}{
+		{name: "firstResponse", quorum: peerQuorum{policy: v1alpha1.FirstResponsePeerQuorumPolicy}, votes: peerVotes{apiErrors: 1, noResponse: 2}, nrPeers: 3, want: true},
+		{name: "firstResponseNoResponse", quorum: peerQuorum{policy: v1alpha1.FirstResponsePeerQuorumPolicy}, votes: peerVotes{noResponse: 3}, nrPeers: 3, want: false},
+		{name: "majority", quorum: peerQuorum{policy: v1alpha1.MajorityPeerQuorumPolicy}, votes: peerVotes{healthy: 1, apiErrors: 1, noResponse: 1}, nrPeers: 3, want: true},
+		{name: "majorityNotReached", quorum: peerQuorum{policy: v1alpha1.MajorityPeerQuorumPolicy}, votes: peerVotes{healthy: 1, noResponse: 1}, nrPeers: 2, want: false},
+		{name: "minAgreeing", quorum: peerQuorum{policy: v1alpha1.MinAgreeingPeerQuorumPolicy, minAgreeingPeers: 2}, votes: peerVotes{healthy: 1, unhealthy: 1}, nrPeers: 4, want: true},
+		{name: "minAgreeingNotReached", quorum: peerQuorum{policy: v1alpha1.MinAgreeingPeerQuorumPolicy, minAgreeingPeers: 2}, votes: peerVotes{healthy: 1, noResponse: 3}, nrPeers: 4, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quorum.isReached(tt.votes, tt.nrPeers); got != tt.want {
+				t.Errorf("isReached() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,11 @@ package controlplane
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -14,15 +14,18 @@ import (
 	"github.com/medik8s/common/pkg/nodes"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/medik8s/self-node-remediation/pkg/certificates"
+	"github.com/medik8s/self-node-remediation/pkg/kubelet"
 	"github.com/medik8s/self-node-remediation/pkg/peers"
 )
 
 const (
-	kubeletPort = "10250"
+	defaultKubeletFailureThreshold = 3
+	kubeletCheckTimeout            = 5 * time.Second
 )
 
 // Manager contains logic and info needed to fence and remediate controlplane nodes
@@ -31,25 +34,50 @@ type Manager struct {
 	nodeRole                     peers.Role
 	endpointHealthCheckUrl       string
 	wasEndpointAccessibleAtStart bool
+	kubeletFailureThreshold      int
+	kubeletFailures              int
+	kubeletHealthChecker         *kubelet.HealthChecker
+	kubeletProbeInterval         time.Duration
+	isKubeletDown                atomic.Bool
 	client                       client.Client
+	cfg                          *rest.Config
 	log                          logr.Logger
 }
 
-// NewManager inits a new Manager return nil if init fails
-func NewManager(nodeName string, myClient client.Client) *Manager {
+// NewManager inits a new Manager return nil if init fails. The kubelet of a worker node is probed with the given interval
+func NewManager(nodeName string, myClient client.Client, cfg *rest.Config, kubeletProbeInterval time.Duration) *Manager {
+	log := ctrl.Log.WithName("controlPlane").WithName("Manager")
 	return &Manager{
 		nodeName:                     nodeName,
 		endpointHealthCheckUrl:       os.Getenv("END_POINT_HEALTH_CHECK_URL"),
+		kubeletFailureThreshold:      getKubeletFailureThreshold(log),
+		kubeletProbeInterval:         kubeletProbeInterval,
 		client:                       myClient,
+		cfg:                          cfg,
 		wasEndpointAccessibleAtStart: false,
-		log:                          ctrl.Log.WithName("controlPlane").WithName("Manager"),
+		log:                          log,
 	}
 }
 
-func (manager *Manager) Start(_ context.Context) error {
+func getKubeletFailureThreshold(log logr.Logger) int {
+	value := os.Getenv("KUBELET_FAILURE_THRESHOLD")
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold < 1 {
+		log.Info("invalid or missing kubelet failure threshold, using the default", "value", value, "default", defaultKubeletFailureThreshold)
+		return defaultKubeletFailureThreshold
+	}
+	return threshold
+}
+
+func (manager *Manager) Start(ctx context.Context) error {
 	if err := manager.initializeManager(); err != nil {
 		return err
 	}
+	if manager.IsControlPlane() {
+		return nil
+	}
+	// the api server might be reachable while the kubelet of a worker node is down, so the kubelet is probed on its own cadence
+	wait.UntilWithContext(ctx, manager.probeKubelet, manager.kubeletProbeInterval)
 	return nil
 }
 
@@ -65,11 +93,17 @@ func (manager *Manager) IsControlPlaneHealthy(workerPeerResponse peers.Response,
 	case peers.UnHealthyBecauseNodeIsIsolated:
 		return canOtherControlPlanesBeReached
 	//reported healthy by worker peers
-	case peers.HealthyBecauseErrorsThresholdNotReached, peers.HealthyBecauseCRNotFound, peers.HealthyBecauseNoPeersResponseNotReachedTimeout:
+	case peers.HealthyBecauseErrorsThresholdNotReached, peers.HealthyBecauseNoPeersResponseNotReachedTimeout:
 		return true
+	//worker peers can access the api server, but this node can't, its kubelet might be down
+	case peers.HealthyBecauseCRNotFound:
+		return manager.isKubeletServiceRunning()
 	//controlPlane node has connection to most workers, we assume it's not isolated (or at least that the controlPlane node that does not have worker peers quorum will reboot)
 	case peers.HealthyBecauseMostPeersCantAccessAPIServer:
 		return manager.isDiagnosticsPassed()
+	//worker peers disagree, this node needs to check itself
+	case peers.HealthyBecausePeersQuorumNotReached:
+		return manager.isDiagnosticsPassed()
 	case peers.HealthyBecauseNoPeersWereFound:
 		return manager.isDiagnosticsPassed() && canOtherControlPlanesBeReached
 
@@ -81,6 +115,21 @@ func (manager *Manager) IsControlPlaneHealthy(workerPeerResponse peers.Response,
 
 }
 
+// IsWorkerHealthy returns whether a worker node is healthy. Worker peers which can access the api server, and don't know
+// of a remediation of this node, report it as healthy, but when this node still needs to ask them its kubelet might be down
+func (manager *Manager) IsWorkerHealthy(workerPeerResponse peers.Response) bool {
+	if workerPeerResponse.Reason != peers.HealthyBecauseCRNotFound {
+		return workerPeerResponse.IsHealthy
+	}
+	manager.log.Info("Starting worker node diagnostics")
+	if manager.IsKubeletDown() {
+		manager.log.Info("kubelet service is down", "node name", manager.nodeName)
+		return false
+	}
+	manager.log.Info("Worker node diagnostics passed successfully")
+	return true
+}
+
 func (manager *Manager) isDiagnosticsPassed() bool {
 	manager.log.Info("Starting control-plane node diagnostics")
 	if manager.isEndpointAccessLost() {
@@ -109,6 +158,13 @@ func (manager *Manager) initializeManager() error {
 	}
 	manager.setNodeRole(node)
 
+	kubeletHealthChecker, err := kubelet.NewHealthChecker(manager.nodeName, kubelet.DefaultPort, manager.cfg)
+	if err != nil {
+		manager.log.Error(err, "could not create the kubelet health checker")
+		return wrapWithInitError(err)
+	}
+	manager.kubeletHealthChecker = kubeletHealthChecker
+
 	manager.wasEndpointAccessibleAtStart = manager.isEndpointAccessible()
 	return nil
 }
@@ -148,27 +204,40 @@ func (manager *Manager) isEndpointAccessible() bool {
 	return true
 }
 
-func (manager *Manager) isKubeletServiceRunning() bool {
-	url := fmt.Sprintf("https://%s:%s/pods", manager.nodeName, kubeletPort)
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-			MinVersion:         certificates.TLSMinVersion,
-		},
-	}
-	httpClient := &http.Client{Transport: tr}
+// IsKubeletDown returns whether the probes of the kubelet of this worker node found it to be down
+func (manager *Manager) IsKubeletDown() bool {
+	return manager.isKubeletDown.Load()
+}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		manager.log.Error(err, "failed to create a kubelet service request", "node name", manager.nodeName)
-		return false
-	}
+// probeKubelet checks the kubelet of this worker node, and keeps the result for the diagnostics
+func (manager *Manager) probeKubelet(_ context.Context) {
+	manager.isKubeletDown.Store(!manager.isKubeletServiceRunning())
+}
 
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		manager.log.Error(err, "kubelet service is down", "node name", manager.nodeName)
-		return false
+// isKubeletServiceRunning checks the kubelet's /healthz endpoint once, and considers the kubelet to be down when the
+// configured number of consecutive checks failed. The failures are counted across the diagnostics, so that a failing
+// kubelet doesn't hold back the api server check with retries. A kubelet whose serving certificate can't be verified
+// can't be checked, so it's considered to be running, without counting a failure
+func (manager *Manager) isKubeletServiceRunning() bool {
+	if manager.kubeletHealthChecker == nil {
+		manager.log.Info("kubelet health checker isn't initialized yet, skipping the kubelet check", "node name", manager.nodeName)
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), kubeletCheckTimeout)
+	defer cancel()
+	if err := manager.kubeletHealthChecker.Check(ctx); err != nil {
+		if errors.Is(err, kubelet.ErrCertificateNotVerified) {
+			manager.log.Error(err, "can't check the kubelet, since its serving certificate isn't signed by the cluster CA, skipping the kubelet check", "node name", manager.nodeName)
+			return true
+		}
+		manager.kubeletFailures++
+		manager.log.Error(err, "kubelet health check failed", "node name", manager.nodeName, "failures", manager.kubeletFailures, "threshold", manager.kubeletFailureThreshold)
+		if manager.kubeletFailures >= manager.kubeletFailureThreshold {
+			manager.log.Info("kubelet service is down", "node name", manager.nodeName)
+			return false
+		}
+		return true
 	}
-	defer resp.Body.Close()
+	manager.kubeletFailures = 0
 	return true
 }
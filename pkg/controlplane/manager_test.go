@@ -0,0 +1,144 @@
+package controlplane
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-logr/logr"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/medik8s/self-node-remediation/pkg/kubelet"
+	"github.com/medik8s/self-node-remediation/pkg/peers"
+)
+
+// newTestManager returns a worker node manager, whose kubelet health checker checks a test server which answers
+// with the status code stored in the returned value. Without trusting the CA, the server's certificate can't be verified
+func newTestManager(t *testing.T, kubeletFailureThreshold int, trustCA bool) (*Manager, *atomic.Int32) {
+	statusCode := &atomic.Int32{}
+	statusCode.Store(http.StatusOK)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(statusCode.Load()))
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse the server url: %v", err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("failed to parse the server port: %v", err)
+	}
+	cfg := &rest.Config{}
+	if trustCA {
+		cfg.TLSClientConfig.CAData = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	}
+	checker, err := kubelet.NewHealthChecker(serverURL.Hostname(), port, cfg)
+	if err != nil {
+		t.Fatalf("failed to create the kubelet health checker: %v", err)
+	}
+
+	return &Manager{
+		nodeName:                "worker-1",
+		nodeRole:                peers.Worker,
+		kubeletFailureThreshold: kubeletFailureThreshold,
+		kubeletHealthChecker:    checker,
+		log:                     logr.Discard(),
+	}, statusCode
+}
+
+func TestIsWorkerHealthy(t *testing.T) {
+	tests := []struct {
+		name              string
+		response          peers.Response
+		kubeletStatusCode int
+		want              bool
+	}{
+		{name: "unhealthyByPeers", response: peers.Response{IsHealthy: false, Reason: peers.UnHealthyBecausePeersResponse}, kubeletStatusCode: http.StatusOK, want: false},
+		{name: "healthyByPeersWithFailingKubelet", response: peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseErrorsThresholdNotReached}, kubeletStatusCode: http.StatusInternalServerError, want: true},
+		{name: "crNotFoundWithHealthyKubelet", response: peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseCRNotFound}, kubeletStatusCode: http.StatusOK, want: true},
+		{name: "crNotFoundWithFailingKubelet", response: peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseCRNotFound}, kubeletStatusCode: http.StatusInternalServerError, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, statusCode := newTestManager(t, 1, true)
+			statusCode.Store(int32(tt.kubeletStatusCode))
+			manager.probeKubelet(context.Background())
+			if got := manager.IsWorkerHealthy(tt.response); got != tt.want {
+				t.Errorf("IsWorkerHealthy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsWorkerHealthyCountsConsecutiveKubeletFailures(t *testing.T) {
+	manager, statusCode := newTestManager(t, 2, true)
+	crNotFound := peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseCRNotFound}
+
+	statusCode.Store(http.StatusInternalServerError)
+	manager.probeKubelet(context.Background())
+	if !manager.IsWorkerHealthy(crNotFound) {
+		t.Errorf("IsWorkerHealthy() = false after the first kubelet failure, want true below the threshold")
+	}
+
+	// a successful check resets the failures
+	statusCode.Store(http.StatusOK)
+	manager.probeKubelet(context.Background())
+	if !manager.IsWorkerHealthy(crNotFound) {
+		t.Errorf("IsWorkerHealthy() = false with a healthy kubelet, want true")
+	}
+
+	statusCode.Store(http.StatusInternalServerError)
+	manager.probeKubelet(context.Background())
+	if !manager.IsWorkerHealthy(crNotFound) {
+		t.Errorf("IsWorkerHealthy() = false after the failures were reset, want true below the threshold")
+	}
+	manager.probeKubelet(context.Background())
+	if manager.IsWorkerHealthy(crNotFound) {
+		t.Errorf("IsWorkerHealthy() = true after consecutive kubelet failures reached the threshold, want false")
+	}
+}
+
+func TestIsWorkerHealthyIgnoresUnverifiedKubeletCertificate(t *testing.T) {
+	manager, _ := newTestManager(t, 1, false)
+	crNotFound := peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseCRNotFound}
+
+	for i := 0; i < 2; i++ {
+		manager.probeKubelet(context.Background())
+		if !manager.IsWorkerHealthy(crNotFound) {
+			t.Errorf("IsWorkerHealthy() = false with an unverified kubelet certificate, want true")
+		}
+	}
+	if manager.kubeletFailures != 0 {
+		t.Errorf("kubeletFailures = %d with an unverified kubelet certificate, want 0", manager.kubeletFailures)
+	}
+}
+
+func TestIsWorkerHealthyUsesLastKubeletProbe(t *testing.T) {
+	manager, statusCode := newTestManager(t, 1, true)
+	crNotFound := peers.Response{IsHealthy: true, Reason: peers.HealthyBecauseCRNotFound}
+
+	statusCode.Store(http.StatusInternalServerError)
+	manager.probeKubelet(context.Background())
+	if !manager.IsKubeletDown() {
+		t.Errorf("IsKubeletDown() = false after a failed probe reached the threshold, want true")
+	}
+
+	// the diagnostics don't probe the kubelet themselves
+	statusCode.Store(http.StatusOK)
+	if manager.IsWorkerHealthy(crNotFound) {
+		t.Errorf("IsWorkerHealthy() = true while the last probe found the kubelet to be down, want false")
+	}
+
+	manager.probeKubelet(context.Background())
+	if manager.IsKubeletDown() {
+		t.Errorf("IsKubeletDown() = true after a successful probe, want false")
+	}
+}
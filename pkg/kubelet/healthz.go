@@ -0,0 +1,85 @@
+package kubelet
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+
+	"github.com/medik8s/self-node-remediation/pkg/certificates"
+)
+
+// DefaultPort is the https port of the kubelet
+const DefaultPort = 10250
+
+// ErrCertificateNotVerified is returned by the check when the serving certificate of the kubelet can't be verified,
+// e.g. because it's self-signed. It doesn't tell anything about whether the kubelet is running
+var ErrCertificateNotVerified = errors.New("kubelet serving certificate can't be verified")
+
+// HealthChecker checks the /healthz endpoint of a kubelet, authenticated with the credentials used for the api server
+type HealthChecker struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHealthChecker creates a HealthChecker for the kubelet of the given node. The serving certificate of the kubelet is
+// verified against the CA of the api server config, so that the credentials aren't sent to an unverified endpoint.
+// This needs kubelet serving certificates which are signed by the cluster CA, e.g. by enabling serverTLSBootstrap
+func NewHealthChecker(nodeName string, port int, cfg *rest.Config) (*HealthChecker, error) {
+	kubeletCfg := rest.CopyConfig(cfg)
+	// the serving certificate of the kubelet is issued for the node name, not for the api server
+	kubeletCfg.TLSClientConfig.ServerName = ""
+	tlsConfig, err := rest.TLSConfigFor(kubeletCfg)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		// the kubelet serves https, even if the api server is reached without it
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.MinVersion = certificates.TLSMinVersion
+
+	transport, err := rest.HTTPWrappersForConfig(kubeletCfg, utilnet.SetTransportDefaults(&http.Transport{TLSClientConfig: tlsConfig}))
+	if err != nil {
+		return nil, err
+	}
+	return &HealthChecker{
+		url:        fmt.Sprintf("https://%s:%d/healthz", nodeName, port),
+		httpClient: &http.Client{Transport: transport},
+	}, nil
+}
+
+// Check returns an error when the kubelet can't be reached or doesn't report that it's healthy.
+// When the serving certificate of the kubelet can't be verified, the error wraps ErrCertificateNotVerified
+func (c *HealthChecker) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if isCertificateVerificationError(err) {
+			return fmt.Errorf("%w: %v", ErrCertificateNotVerified, err)
+		}
+		return fmt.Errorf("kubelet healthz endpoint error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kubelet healthz endpoint status code: %v", resp.StatusCode)
+	}
+	return nil
+}
+
+// isCertificateVerificationError returns whether the given error is caused by a serving certificate which can't be verified
+func isCertificateVerificationError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verificationErr) || errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr)
+}
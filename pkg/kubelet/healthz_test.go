@@ -0,0 +1,85 @@
+package kubelet
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+const testToken = "test-token"
+
+// newTestKubelet starts a tls server which answers /healthz with the given status code for requests with the test token,
+// and returns an api server config trusting its certificate, and the node name and the port of the server
+func newTestKubelet(t *testing.T, statusCode int, maxVersion uint16) (*rest.Config, string, int) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" || r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(statusCode)
+	}))
+	server.TLS = &tls.Config{MaxVersion: maxVersion}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse the server url: %v", err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatalf("failed to parse the server port: %v", err)
+	}
+	cfg := &rest.Config{
+		Host:        "https://api.example.com:6443",
+		BearerToken: testToken,
+		TLSClientConfig: rest.TLSClientConfig{
+			ServerName: "api.example.com",
+			CAData:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+		},
+	}
+	return cfg, serverURL.Hostname(), port
+}
+
+func TestHealthCheckerCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		statusCode     int
+		maxVersion     uint16
+		trustCA        bool
+		wantErr        bool
+		wantUnverified bool
+	}{
+		{name: "healthy", statusCode: http.StatusOK, trustCA: true, wantErr: false},
+		{name: "unhealthy", statusCode: http.StatusInternalServerError, trustCA: true, wantErr: true},
+		{name: "untrustedCertificate", statusCode: http.StatusOK, trustCA: false, wantErr: true, wantUnverified: true},
+		{name: "belowMinTLSVersion", statusCode: http.StatusOK, maxVersion: tls.VersionTLS12, trustCA: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, nodeName, port := newTestKubelet(t, tt.statusCode, tt.maxVersion)
+			if !tt.trustCA {
+				cfg.TLSClientConfig.CAData = nil
+			}
+			checker, err := NewHealthChecker(nodeName, port, cfg)
+			if err != nil {
+				t.Fatalf("NewHealthChecker() error = %v", err)
+			}
+			err = checker.Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrCertificateNotVerified) != tt.wantUnverified {
+				t.Errorf("Check() error = %v, wantUnverified %v", err, tt.wantUnverified)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	namespace = "self_node_remediation"
+
+	strategyLabel = "strategy"
+	outcomeLabel  = "outcome"
+	phaseLabel    = "phase"
+	nodeLabel     = "node"
+	methodLabel   = "method"
+	voteLabel     = "vote"
+
+	// RebootMethodBootIDChanged is used when the reboot was detected by a new boot ID of the node
+	RebootMethodBootIDChanged = "boot_id_changed"
+	// RebootMethodAssumed is used when the reboot is assumed, because the safe time to reboot passed
+	RebootMethodAssumed = "assumed"
+
+	// PeerVoteHealthy is used for peers which answered that the node is healthy
+	PeerVoteHealthy = "healthy"
+	// PeerVoteUnhealthy is used for peers which answered that the node is unhealthy
+	PeerVoteUnhealthy = "unhealthy"
+	// PeerVoteApiError is used for peers which answered that they can't access the api server
+	PeerVoteApiError = "api_error"
+	// PeerVoteNoResponse is used for peers which didn't answer
+	PeerVoteNoResponse = "no_response"
+)
+
+var (
+	remediations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "remediations_total",
+		Help:      "Number of finished remediations by strategy and outcome",
+	}, []string{strategyLabel, outcomeLabel})
+
+	phaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "phase_duration_seconds",
+		Help:      "Time spent by a remediation in each phase",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 180, 300, 600, 1200, 1800, 3600},
+	}, []string{phaseLabel})
+
+	remediationsInProgress = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "remediations_in_progress",
+		Help:      "Number of in-flight remediations per node",
+	}, []string{nodeLabel})
+
+	reboots = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reboots_total",
+		Help:      "Number of reboots of remediated nodes, by the method used for detecting the reboot",
+	}, []string{methodLabel})
+
+	peerVotes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "peer_votes_total",
+		Help:      "Number of answers of peers, which the agent asked whether its node is healthy, by vote",
+	}, []string{voteLabel})
+)
+
+func init() {
+	metrics.Registry.MustRegister(remediations, phaseDuration, remediationsInProgress, reboots, peerVotes)
+}
+
+// ObserveRemediationFinished counts a remediation which reached a final outcome
+func ObserveRemediationFinished(strategy, outcome string) {
+	remediations.WithLabelValues(strategy, outcome).Inc()
+}
+
+// ObservePhaseDuration records the time a remediation spent in the given phase
+func ObservePhaseDuration(phase string, duration time.Duration) {
+	phaseDuration.WithLabelValues(phase).Observe(duration.Seconds())
+}
+
+// SetRemediationInProgress marks whether the given node is currently being remediated
+func SetRemediationInProgress(nodeName string, inProgress bool) {
+	if inProgress {
+		remediationsInProgress.WithLabelValues(nodeName).Set(1)
+		return
+	}
+	remediationsInProgress.DeleteLabelValues(nodeName)
+}
+
+// ObserveReboot counts a reboot of a remediated node, which was detected with the given method
+func ObserveReboot(method string) {
+	reboots.WithLabelValues(method).Inc()
+}
+
+// ObservePeerVotes counts the given number of peer answers with the given vote
+func ObservePeerVotes(vote string, count int) {
+	peerVotes.WithLabelValues(vote).Add(float64(count))
+}
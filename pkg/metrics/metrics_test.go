@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// getMetric returns the current value of the given metric
+func getMetric(t *testing.T, metric prometheus.Metric) *dto.Metric {
+	t.Helper()
+	m := &dto.Metric{}
+	if err := metric.Write(m); err != nil {
+		t.Fatalf("failed to read metric: %v", err)
+	}
+	return m
+}
+
+func TestObserveRemediationFinished(t *testing.T) {
+	counter := remediations.WithLabelValues("ResourceDeletion", "success")
+	before := getMetric(t, counter).GetCounter().GetValue()
+
+	ObserveRemediationFinished("ResourceDeletion", "success")
+	ObserveRemediationFinished("ResourceDeletion", "success")
+	ObserveRemediationFinished("ResourceDeletion", "failure")
+
+	if got := getMetric(t, counter).GetCounter().GetValue() - before; got != 2 {
+		t.Errorf("remediations_total{strategy=ResourceDeletion,outcome=success} increased by %v, want 2", got)
+	}
+}
+
+func TestObservePhaseDuration(t *testing.T) {
+	histogram := phaseDuration.WithLabelValues("Fencing-Started").(prometheus.Histogram)
+	before := getMetric(t, histogram).GetHistogram()
+
+	ObservePhaseDuration("Fencing-Started", 90*time.Second)
+
+	after := getMetric(t, histogram).GetHistogram()
+	if got := after.GetSampleCount() - before.GetSampleCount(); got != 1 {
+		t.Errorf("phase_duration_seconds{phase=Fencing-Started} sample count increased by %v, want 1", got)
+	}
+	if got := after.GetSampleSum() - before.GetSampleSum(); got != 90 {
+		t.Errorf("phase_duration_seconds{phase=Fencing-Started} sample sum increased by %v, want 90", got)
+	}
+}
+
+func TestSetRemediationInProgress(t *testing.T) {
+	SetRemediationInProgress("node1", true)
+	if got := getMetric(t, remediationsInProgress.WithLabelValues("node1")).GetGauge().GetValue(); got != 1 {
+		t.Errorf("remediations_in_progress{node=node1} = %v, want 1", got)
+	}
+
+	SetRemediationInProgress("node1", false)
+	if remediationsInProgress.DeleteLabelValues("node1") {
+		t.Errorf("remediations_in_progress{node=node1} still exists, want it removed")
+	}
+}
+
+func TestObserveReboot(t *testing.T) {
+	bootIDChanged := reboots.WithLabelValues(RebootMethodBootIDChanged)
+	assumed := reboots.WithLabelValues(RebootMethodAssumed)
+	bootIDChangedBefore := getMetric(t, bootIDChanged).GetCounter().GetValue()
+	assumedBefore := getMetric(t, assumed).GetCounter().GetValue()
+
+	ObserveReboot(RebootMethodBootIDChanged)
+
+	if got := getMetric(t, bootIDChanged).GetCounter().GetValue() - bootIDChangedBefore; got != 1 {
+		t.Errorf("reboots_total{method=%s} increased by %v, want 1", RebootMethodBootIDChanged, got)
+	}
+	if got := getMetric(t, assumed).GetCounter().GetValue() - assumedBefore; got != 0 {
+		t.Errorf("reboots_total{method=%s} increased by %v, want 0", RebootMethodAssumed, got)
+	}
+}
+
+func TestObservePeerVotes(t *testing.T) {
+	counter := peerVotes.WithLabelValues(PeerVoteNoResponse)
+	before := getMetric(t, counter).GetCounter().GetValue()
+
+	ObservePeerVotes(PeerVoteNoResponse, 3)
+
+	if got := getMetric(t, counter).GetCounter().GetValue() - before; got != 3 {
+		t.Errorf("peer_votes_total{vote=%s} increased by %v, want 3", PeerVoteNoResponse, got)
+	}
+}
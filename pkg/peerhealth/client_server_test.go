@@ -9,7 +9,9 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/medik8s/self-node-remediation/api"
@@ -122,4 +124,142 @@ var _ = Describe("Checking health using grpc client and server", func() {
 
 	})
 
+	Describe("for a node with a dry run SNR", func() {
+		const dryRunNodeName = "dry-run-node"
+
+		BeforeEach(func() {
+			createSnr(dryRunNodeName, v1alpha1.SelfNodeRemediationSpec{DryRun: true}, nil)
+		})
+
+		It("should return healthy, so that the node doesn't reboot itself", func() {
+			Expect(phServer.isHealthyBySnr(context.Background(), dryRunNodeName, "default")).To(Equal(api.Healthy))
+		})
+	})
+
+	Describe("for a node with a permanently failed SNR", func() {
+		const permanentlyFailedNodeName = "permanently-failed-node"
+
+		BeforeEach(func() {
+			createSnr(permanentlyFailedNodeName, v1alpha1.SelfNodeRemediationSpec{}, func(status *v1alpha1.SelfNodeRemediationStatus) {
+				meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+					Type:   v1alpha1.PermanentlyFailedConditionType,
+					Status: metav1.ConditionTrue,
+					Reason: "RebootLoopDetected",
+				})
+			})
+		})
+
+		It("should return healthy, so that the node doesn't keep rebooting itself", func() {
+			Expect(phServer.isHealthyBySnr(context.Background(), permanentlyFailedNodeName, "default")).To(Equal(api.Healthy))
+		})
+	})
+
+	Describe("for a node with a SNR waiting for the max concurrent fencing limit", func() {
+		const waitingNodeName = "waiting-node"
+
+		BeforeEach(func() {
+			createSnr(waitingNodeName, v1alpha1.SelfNodeRemediationSpec{}, func(status *v1alpha1.SelfNodeRemediationStatus) {
+				status.Phase = pointer.String("Waiting")
+			})
+		})
+
+		It("should return healthy, so that the node doesn't bypass the limit by rebooting itself", func() {
+			Expect(phServer.isHealthyBySnr(context.Background(), waitingNodeName, "default")).To(Equal(api.Healthy))
+		})
+	})
+
+	Describe("for a node with a SNR postponed by a blackout window", func() {
+		const postponedNodeName = "postponed-node"
+
+		BeforeEach(func() {
+			createSnr(postponedNodeName, v1alpha1.SelfNodeRemediationSpec{}, func(status *v1alpha1.SelfNodeRemediationStatus) {
+				status.Phase = pointer.String("Postponed")
+			})
+		})
+
+		It("should return healthy, so that the node doesn't reboot itself during the blackout window", func() {
+			Expect(phServer.isHealthyBySnr(context.Background(), postponedNodeName, "default")).To(Equal(api.Healthy))
+		})
+	})
+
+	Describe("for a node with a SNR paused by a suspected zone failure", func() {
+		const zonePausedNodeName = "zone-paused-node"
+
+		BeforeEach(func() {
+			createSnr(zonePausedNodeName, v1alpha1.SelfNodeRemediationSpec{}, func(status *v1alpha1.SelfNodeRemediationStatus) {
+				meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+					Type:   v1alpha1.ZoneFailureSuspectedConditionType,
+					Status: metav1.ConditionTrue,
+					Reason: "ZoneFailureDetected",
+				})
+			})
+		})
+
+		It("should return healthy, so that the nodes of the zone don't reboot themselves", func() {
+			Expect(phServer.isHealthyBySnr(context.Background(), zonePausedNodeName, "default")).To(Equal(api.Healthy))
+		})
+	})
+
+	Describe("for a node of a Cluster API machine", func() {
+		const clusterAPINodeName = "cluster-api-node"
+
+		BeforeEach(func() {
+			By("creating a node with the Cluster API machine annotation")
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        clusterAPINodeName,
+					Annotations: map[string]string{"cluster.x-k8s.io/machine": "default/unhealthy-machine"},
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), node)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(context.Background(), node)).To(Succeed())
+			})
+		})
+
+		It("should return healthy when there is no SNR for the machine", func() {
+			Expect(phServer.isHealthyMachine(context.Background(), clusterAPINodeName, "default")).To(Equal(api.Healthy))
+		})
+
+		When("there is a SNR for the machine", func() {
+			BeforeEach(func() {
+				snr := &v1alpha1.SelfNodeRemediation{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "unhealthy-machine",
+						Namespace: "default",
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), snr)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(k8sClient.Delete(context.Background(), snr)).To(Succeed())
+				})
+			})
+
+			It("should return unhealthy", func() {
+				Expect(phServer.isHealthyMachine(context.Background(), clusterAPINodeName, "default")).To(Equal(api.Unhealthy))
+			})
+		})
+	})
+
 })
+
+// createSnr creates a SNR with the given spec, updates its status with the given function if any,
+// and deletes the SNR when the test is done
+func createSnr(name string, spec v1alpha1.SelfNodeRemediationSpec, updateStatus func(status *v1alpha1.SelfNodeRemediationStatus)) {
+	snr := &v1alpha1.SelfNodeRemediation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: spec,
+	}
+	Expect(k8sClient.Create(context.Background(), snr)).To(Succeed())
+	DeferCleanup(func() {
+		Expect(k8sClient.Delete(context.Background(), snr)).To(Succeed())
+	})
+
+	if updateStatus != nil {
+		updateStatus(&snr.Status)
+		Expect(k8sClient.Status().Update(context.Background(), snr)).To(Succeed())
+	}
+}
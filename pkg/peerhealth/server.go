@@ -13,6 +13,7 @@ import (
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
@@ -26,7 +27,6 @@ import (
 
 const (
 	connectionTimeout = 5 * time.Second
-	machineAnnotation = "machine.openshift.io/machine" //todo this is openshift specific
 	//IMPORTANT! this MUST be less than PeerRequestTimeout in apicheck
 	//The difference between them should allow some time for sending the request over the network
 	//todo enforce this
@@ -44,6 +44,11 @@ var (
 		Version:  corev1.SchemeGroupVersion.Version,
 		Resource: "nodes",
 	}
+	// the node annotations which reference the machine of the node, of OpenShift and of Cluster API machines
+	machineAnnotations = []string{
+		"machine.openshift.io/machine",
+		"cluster.x-k8s.io/machine",
+	}
 )
 
 type Server struct {
@@ -156,9 +161,7 @@ func (s Server) isHealthyMachine(ctx context.Context, nodeName string, namespace
 		return selfNodeRemediationApis.ApiError
 	}
 
-	ann := node.GetAnnotations()
-	namespacedMachine, exists := ann[machineAnnotation]
-
+	namespacedMachine, exists := getNamespacedMachine(node.GetAnnotations())
 	if !exists {
 		s.log.Info("node doesn't have machine annotation")
 		return selfNodeRemediationApis.Unhealthy //todo is this the correct response?
@@ -173,11 +176,22 @@ func (s Server) isHealthyMachine(ctx context.Context, nodeName string, namespace
 	return s.isHealthyBySnr(ctx, machineName, namespace)
 }
 
+// getNamespacedMachine returns the namespaced name of the machine of a node, from whichever of the machine annotations
+// the node has
+func getNamespacedMachine(nodeAnnotations map[string]string) (string, bool) {
+	for _, machineAnnotation := range machineAnnotations {
+		if namespacedMachine, exists := nodeAnnotations[machineAnnotation]; exists {
+			return namespacedMachine, true
+		}
+	}
+	return "", false
+}
+
 func (s Server) isHealthyBySnr(ctx context.Context, snrName string, snrNamespace string) selfNodeRemediationApis.HealthCheckResponseCode {
 	apiCtx, cancelFunc := context.WithTimeout(ctx, apiServerTimeout)
 	defer cancelFunc()
 
-	_, err := s.client.Resource(snrRes).Namespace(snrNamespace).Get(apiCtx, snrName, metav1.GetOptions{})
+	unstructuredSnr, err := s.client.Resource(snrRes).Namespace(snrNamespace).Get(apiCtx, snrName, metav1.GetOptions{})
 	if err != nil {
 		if apiErrors.IsNotFound(err) {
 			s.log.Info("node is healthy")
@@ -187,6 +201,17 @@ func (s Server) isHealthyBySnr(ctx context.Context, snrName string, snrNamespace
 		return selfNodeRemediationApis.ApiError
 	}
 
+	snr := &v1alpha1.SelfNodeRemediation{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredSnr.UnstructuredContent(), snr); err != nil {
+		s.log.Error(err, "failed to convert SNR, node is unhealthy", "SNR name", snrName)
+		return selfNodeRemediationApis.Unhealthy
+	}
+	// the node can't tell by itself that its reboot is held back, when it can't reach the api server
+	if reason := controllers.GetRebootHoldReason(snr); reason != "" {
+		s.log.Info("node has a SNR, but its reboot is held back, node is healthy", "reason", reason)
+		return selfNodeRemediationApis.Healthy
+	}
+
 	s.log.Info("node is unhealthy")
 	return selfNodeRemediationApis.Unhealthy
 }
@@ -30,25 +30,28 @@ const (
 
 type Peers struct {
 	client.Reader
-	log                                              logr.Logger
-	workerPeerSelector, controlPlanePeerSelector     labels.Selector
-	peerUpdateInterval                               time.Duration
-	myNodeName                                       string
-	mutex                                            sync.Mutex
-	apiServerTimeout                                 time.Duration
-	workerPeersAddresses, controlPlanePeersAddresses [][]v1.NodeAddress
+	log                                          logr.Logger
+	workerPeerSelector, controlPlanePeerSelector labels.Selector
+	peerUpdateInterval                           time.Duration
+	myNodeName                                   string
+	mutex                                        sync.Mutex
+	apiServerTimeout                             time.Duration
+	// rackLabel is the node label which defines the rack failure domain, in addition to the zone
+	rackLabel                      string
+	workerPeers, controlPlanePeers []peer
 }
 
-func New(myNodeName string, peerUpdateInterval time.Duration, reader client.Reader, log logr.Logger, apiServerTimeout time.Duration) *Peers {
+func New(myNodeName string, peerUpdateInterval time.Duration, reader client.Reader, log logr.Logger, apiServerTimeout time.Duration, rackLabel string) *Peers {
 	return &Peers{
-		Reader:                     reader,
-		log:                        log,
-		peerUpdateInterval:         peerUpdateInterval,
-		myNodeName:                 myNodeName,
-		mutex:                      sync.Mutex{},
-		apiServerTimeout:           apiServerTimeout,
-		workerPeersAddresses:       [][]v1.NodeAddress{},
-		controlPlanePeersAddresses: [][]v1.NodeAddress{},
+		Reader:             reader,
+		log:                log,
+		peerUpdateInterval: peerUpdateInterval,
+		myNodeName:         myNodeName,
+		mutex:              sync.Mutex{},
+		apiServerTimeout:   apiServerTimeout,
+		rackLabel:          rackLabel,
+		workerPeers:        []peer{},
+		controlPlanePeers:  []peer{},
 	}
 }
 
@@ -88,18 +91,18 @@ func (p *Peers) Start(ctx context.Context) error {
 }
 
 func (p *Peers) updateWorkerPeers(ctx context.Context) {
-	setterFunc := func(addresses [][]v1.NodeAddress) { p.workerPeersAddresses = addresses }
+	setterFunc := func(peers []peer) { p.workerPeers = peers }
 	selectorGetter := func() labels.Selector { return p.workerPeerSelector }
 	p.updatePeers(ctx, selectorGetter, setterFunc)
 }
 
 func (p *Peers) updateControlPlanePeers(ctx context.Context) {
-	setterFunc := func(addresses [][]v1.NodeAddress) { p.controlPlanePeersAddresses = addresses }
+	setterFunc := func(peers []peer) { p.controlPlanePeers = peers }
 	selectorGetter := func() labels.Selector { return p.controlPlanePeerSelector }
 	p.updatePeers(ctx, selectorGetter, setterFunc)
 }
 
-func (p *Peers) updatePeers(ctx context.Context, getSelector func() labels.Selector, setAddresses func(addresses [][]v1.NodeAddress)) {
+func (p *Peers) updatePeers(ctx context.Context, getSelector func() labels.Selector, setPeers func(peers []peer)) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
@@ -111,36 +114,46 @@ func (p *Peers) updatePeers(ctx context.Context, getSelector func() labels.Selec
 	if err := p.List(readerCtx, &nodes, client.MatchingLabelsSelector{Selector: getSelector()}); err != nil {
 		if errors.IsNotFound(err) {
 			// we are the only node at the moment... reset peerList
-			p.workerPeersAddresses = [][]v1.NodeAddress{}
+			p.workerPeers = []peer{}
 		}
 		p.log.Error(err, "failed to update peer list")
 		return
 	}
 
 	nodesCount := len(nodes.Items)
-	addresses := make([][]v1.NodeAddress, nodesCount)
+	peers := make([]peer, nodesCount)
 	for i, node := range nodes.Items {
-		addresses[i] = node.Status.Addresses
+		peers[i] = peer{
+			addresses: node.Status.Addresses,
+			zone:      node.Labels[v1.LabelTopologyZone],
+		}
+		if p.rackLabel != "" {
+			peers[i].rack = node.Labels[p.rackLabel]
+		}
 	}
-	setAddresses(addresses)
+	setPeers(peers)
 }
 
+// GetPeersAddresses returns the addresses of the peers in random order, but spread over their zones and racks,
+// so that the first peers cover as many failure domains as possible
 func (p *Peers) GetPeersAddresses(role Role) [][]v1.NodeAddress {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	var addresses [][]v1.NodeAddress
+	var peers []peer
 	if role == Worker {
-		addresses = p.workerPeersAddresses
+		peers = p.workerPeers
 	} else {
-		addresses = p.controlPlanePeersAddresses
+		peers = p.controlPlanePeers
 	}
+	peers = sortForSampling(peers)
+
 	//we don't want the caller to be able to change the addresses
 	//so we create a deep copy and return it
-	addressesCopy := make([][]v1.NodeAddress, len(addresses))
+	addressesCopy := make([][]v1.NodeAddress, len(peers))
 	for i := range addressesCopy {
-		addressesCopy[i] = make([]v1.NodeAddress, len(addresses[i]))
-		copy(addressesCopy, addresses)
+		addressesCopy[i] = make([]v1.NodeAddress, len(peers[i].addresses))
+		copy(addressesCopy[i], peers[i].addresses)
 	}
 
 	return addressesCopy
@@ -13,6 +13,7 @@ const (
 	HealthyBecauseNoPeersResponseNotReachedTimeout reason = "No response from peer. The duration of peer not responding hasn't passed the threshold so still considered healthy"
 	HealthyBecauseNoPeersWereFound                 reason = "No Peers where found, node is considered healthy"
 	HealthyBecauseMostPeersCantAccessAPIServer     reason = "Most peers couldn't access API server, node is considered healthy"
+	HealthyBecausePeersQuorumNotReached            reason = "Peers didn't reach a quorum on the node's health, node is considered healthy"
 
 	UnHealthyBecausePeersResponse  reason = "Node is reported unhealthy by it's peers"
 	UnHealthyBecauseNodeIsIsolated reason = "Node is isolated, node is considered unhealthy"
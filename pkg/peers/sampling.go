@@ -0,0 +1,63 @@
+package peers
+
+import (
+	"math/rand"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// peer is a peer node with the failure domains it belongs to
+type peer struct {
+	addresses []v1.NodeAddress
+	zone      string
+	rack      string
+}
+
+// sortForSampling returns the peers in random order, but spread over the failure domains: consecutive peers are in
+// different zones as long as possible, and the peers of each zone are spread over its racks the same way.
+// That way a batch of peers taken from the start of the result covers as many failure domains as possible.
+func sortForSampling(peers []peer) []peer {
+	var zones [][]peer
+	for _, zonePeers := range groupBy(peers, func(p peer) string { return p.zone }) {
+		zones = append(zones, interleave(groupBy(zonePeers, func(p peer) string { return p.rack })))
+	}
+	return interleave(zones)
+}
+
+// groupBy groups the peers by the given key, and returns the groups and the peers in each group in random order
+func groupBy(peers []peer, key func(peer) string) [][]peer {
+	groupIndexes := map[string]int{}
+	var groups [][]peer
+	for _, p := range peers {
+		index, exists := groupIndexes[key(p)]
+		if !exists {
+			index = len(groups)
+			groupIndexes[key(p)] = index
+			groups = append(groups, nil)
+		}
+		groups[index] = append(groups[index], p)
+	}
+
+	for _, group := range groups {
+		rand.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+	}
+	rand.Shuffle(len(groups), func(i, j int) { groups[i], groups[j] = groups[j], groups[i] })
+	return groups
+}
+
+// interleave takes one peer of each group in turn, until all groups are empty
+func interleave(groups [][]peer) []peer {
+	var peers []peer
+	for taken := 0; ; taken++ {
+		isDone := true
+		for _, group := range groups {
+			if taken < len(group) {
+				peers = append(peers, group[taken])
+				isDone = false
+			}
+		}
+		if isDone {
+			return peers
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package peers
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func newPeer(address, zone, rack string) peer {
+	return peer{addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: address}}, zone: zone, rack: rack}
+}
+
+func TestSortForSamplingSpreadsZones(t *testing.T) {
+	peers := []peer{
+		newPeer("10.0.0.1", "zone-a", ""),
+		newPeer("10.0.0.2", "zone-a", ""),
+		newPeer("10.0.0.3", "zone-a", ""),
+		newPeer("10.0.0.4", "zone-b", ""),
+		newPeer("10.0.0.5", "zone-b", ""),
+		newPeer("10.0.0.6", "zone-c", ""),
+	}
+	for i := 0; i < 20; i++ {
+		sorted := sortForSampling(peers)
+		if len(sorted) != len(peers) {
+			t.Fatalf("sortForSampling() returned %d peers, want %d", len(sorted), len(peers))
+		}
+		// the first batch covers all zones
+		zones := map[string]bool{}
+		for _, p := range sorted[:3] {
+			zones[p.zone] = true
+		}
+		if len(zones) != 3 {
+			t.Errorf("sortForSampling() first peers = %v, want one of each zone", sorted[:3])
+		}
+		addresses := map[string]bool{}
+		for _, p := range sorted {
+			addresses[p.addresses[0].Address] = true
+		}
+		if len(addresses) != len(peers) {
+			t.Errorf("sortForSampling() = %v, want every peer once", sorted)
+		}
+	}
+}
+
+func TestSortForSamplingSpreadsRacks(t *testing.T) {
+	peers := []peer{
+		newPeer("10.0.0.1", "zone-a", "rack-1"),
+		newPeer("10.0.0.2", "zone-a", "rack-1"),
+		newPeer("10.0.0.3", "zone-a", "rack-2"),
+		newPeer("10.0.0.4", "zone-a", "rack-2"),
+	}
+	for i := 0; i < 20; i++ {
+		sorted := sortForSampling(peers)
+		for j := 1; j < len(sorted); j++ {
+			if sorted[j].rack == sorted[j-1].rack {
+				t.Errorf("sortForSampling() = %v, want alternating racks", sorted)
+				break
+			}
+		}
+	}
+}
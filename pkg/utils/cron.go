@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed cron expression with the five standard fields: minute, hour, day of month, month and day of week
+type CronSchedule struct {
+	minutes     []bool
+	hours       []bool
+	daysOfMonth []bool
+	months      []bool
+	daysOfWeek  []bool
+	// standard cron matches a day when either the day of month or the day of week matches, if both are restricted
+	isDayOfMonthRestricted bool
+	isDayOfWeekRestricted  bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	// both 0 and 7 are Sunday
+	{name: "day of week", min: 0, max: 7},
+}
+
+// ParseCronSchedule parses a cron expression with the five standard fields.
+// Each field is either "*" or a comma separated list of values and ranges (e.g. "1-5"), optionally with a step (e.g. "*/15")
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected %d fields, found %d", expr, len(cronFields), len(fields))
+	}
+
+	parsed := make([][]bool, len(fields))
+	for i, field := range fields {
+		values, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %v", expr, err)
+		}
+		parsed[i] = values
+	}
+
+	// Sunday may be given as 7
+	daysOfWeek := parsed[4]
+	daysOfWeek[0] = daysOfWeek[0] || daysOfWeek[7]
+
+	return &CronSchedule{
+		minutes:                parsed[0],
+		hours:                  parsed[1],
+		daysOfMonth:            parsed[2],
+		months:                 parsed[3],
+		daysOfWeek:             daysOfWeek[:7],
+		isDayOfMonthRestricted: !strings.HasPrefix(fields[2], "*"),
+		isDayOfWeekRestricted:  !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, spec cronField) ([]bool, error) {
+	values := make([]bool, spec.max+1)
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rangeExpr = item[:i]
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step in %s field: %q", spec.name, item)
+			}
+		}
+
+		start, end := spec.min, spec.max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], spec); err != nil {
+				return nil, err
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = parseCronValue(bounds[1], spec); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				// "a/n" means every n starting at a
+				end = spec.max
+			}
+			if end < start {
+				return nil, fmt.Errorf("invalid range in %s field: %q", spec.name, item)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+func parseCronValue(value string, spec cronField) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < spec.min || v > spec.max {
+		return 0, fmt.Errorf("invalid value in %s field: %q, expected a number between %d and %d", spec.name, value, spec.min, spec.max)
+	}
+	return v, nil
+}
+
+// Matches returns true if the schedule fires on the minute of the given time
+func (s *CronSchedule) Matches(t time.Time) bool {
+	if !s.minutes[t.Minute()] || !s.hours[t.Hour()] || !s.months[int(t.Month())] {
+		return false
+	}
+	dayOfMonthMatches, dayOfWeekMatches := s.daysOfMonth[t.Day()], s.daysOfWeek[int(t.Weekday())]
+	if s.isDayOfMonthRestricted && s.isDayOfWeekRestricted {
+		return dayOfMonthMatches || dayOfWeekMatches
+	}
+	return dayOfMonthMatches && dayOfWeekMatches
+}
+
+// LastActivation returns the latest time the schedule fired within the given period before now, and false if it didn't fire in that period
+func (s *CronSchedule) LastActivation(now time.Time, period time.Duration) (time.Time, bool) {
+	earliest := now.Add(-period)
+	for t := now.Truncate(time.Minute); t.After(earliest); t = t.Add(-time.Minute) {
+		if s.Matches(t) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		//valid use-cases
+		{name: "allWildcards", expr: "* * * * *", wantErr: false},
+		{name: "values", expr: "30 22 1 12 5", wantErr: false},
+		{name: "rangesAndLists", expr: "0,30 8-18 * 1-6,9 1-5", wantErr: false},
+		{name: "steps", expr: "*/15 0-23/2 * * *", wantErr: false},
+		{name: "sundayAsSeven", expr: "0 0 * * 7", wantErr: false},
+
+		//invalid use-cases
+		{name: "empty", expr: "", wantErr: true},
+		{name: "tooFewFields", expr: "* * * *", wantErr: true},
+		{name: "tooManyFields", expr: "* * * * * *", wantErr: true},
+		{name: "minuteOutOfRange", expr: "60 * * * *", wantErr: true},
+		{name: "dayOfMonthZero", expr: "* * 0 * *", wantErr: true},
+		{name: "reversedRange", expr: "* 18-8 * * *", wantErr: true},
+		{name: "zeroStep", expr: "*/0 * * * *", wantErr: true},
+		{name: "names", expr: "* * * JAN MON", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCronSchedule(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("ParseCronSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronScheduleMatches(t *testing.T) {
+	// 2023-06-02 is a Friday
+	friday := time.Date(2023, time.June, 2, 22, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		expr        string
+		t           time.Time
+		wantMatches bool
+	}{
+		{name: "allWildcards", expr: "* * * * *", t: friday, wantMatches: true},
+		{name: "exactTime", expr: "30 22 * * *", t: friday, wantMatches: true},
+		{name: "otherMinute", expr: "31 22 * * *", t: friday, wantMatches: false},
+		{name: "step", expr: "*/15 * * * *", t: friday, wantMatches: true},
+		{name: "stepFromValue", expr: "10/20 * * * *", t: friday, wantMatches: true},
+		{name: "weekdays", expr: "* * * * 1-5", t: friday, wantMatches: true},
+		{name: "weekend", expr: "* * * * 0,6", t: friday, wantMatches: false},
+		{name: "sundayAsSeven", expr: "* * * * 7", t: friday.AddDate(0, 0, 2), wantMatches: true},
+		{name: "otherMonth", expr: "* * * 7 *", t: friday, wantMatches: false},
+		// when both day fields are restricted, either of them matches
+		{name: "dayOfMonthOrDayOfWeek", expr: "* * 15 * 5", t: friday, wantMatches: true},
+		{name: "dayOfMonthAndWildcardDayOfWeek", expr: "* * 15 * *", t: friday, wantMatches: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule() error = %v", err)
+			}
+			if matches := schedule.Matches(tt.t); matches != tt.wantMatches {
+				t.Errorf("Matches() = %v, want %v", matches, tt.wantMatches)
+			}
+		})
+	}
+}
+
+func TestCronScheduleLastActivation(t *testing.T) {
+	now := time.Date(2023, time.June, 2, 22, 30, 45, 0, time.UTC)
+	tests := []struct {
+		name      string
+		expr      string
+		period    time.Duration
+		wantFound bool
+		wantTime  time.Time
+	}{
+		{name: "firesNow", expr: "30 22 * * *", period: time.Hour, wantFound: true, wantTime: time.Date(2023, time.June, 2, 22, 30, 0, 0, time.UTC)},
+		{name: "firedWithinPeriod", expr: "0 22 * * *", period: time.Hour, wantFound: true, wantTime: time.Date(2023, time.June, 2, 22, 0, 0, 0, time.UTC)},
+		{name: "latestActivation", expr: "0 * * * *", period: 3 * time.Hour, wantFound: true, wantTime: time.Date(2023, time.June, 2, 22, 0, 0, 0, time.UTC)},
+		{name: "firedBeforePeriod", expr: "0 20 * * *", period: time.Hour, wantFound: false},
+		{name: "firesLater", expr: "0 23 * * *", period: time.Hour, wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule() error = %v", err)
+			}
+			activation, found := schedule.LastActivation(now, tt.period)
+			if found != tt.wantFound || !activation.Equal(tt.wantTime) {
+				t.Errorf("LastActivation() = %v, %v, want %v, %v", activation, found, tt.wantTime, tt.wantFound)
+			}
+		})
+	}
+}